@@ -0,0 +1,63 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	commit "github.com/GiulianoPoeta99/conventional_commits_cli/internal"
+	ui "github.com/GiulianoPoeta99/conventional_commits_cli/internal/ui"
+)
+
+// loadAmended pre-fills the wizard with the message of HEAD for --amend. Amending a
+// commit already pushed to the upstream branch rewrites published history, so it is
+// refused unless --force is given.
+func (w *wizard) loadAmended() error {
+	head, err := w.git.CommitAt("HEAD")
+	if err != nil {
+		return fmt.Errorf("nothing to amend: %w", err)
+	}
+
+	if !w.opts.Force {
+		upstream, err := w.pushedTo("HEAD")
+		if err != nil {
+			return err
+		}
+		if upstream != "" {
+			return fmt.Errorf("HEAD (%s) was already pushed to %s; amending it rewrites published history (use --force to amend anyway)", head.ShortHash(), upstream)
+		}
+	}
+
+	// Messages that are not Conventional Commits still pre-fill what could be read.
+	config, _ := commit.ParseCommitMessage(head.Message, w.settings.Types, w.settings.Emojis)
+	w.config = config
+	w.amended = &config
+	return nil
+}
+
+// pushedTo returns the upstream branch that already contains the commit at revision,
+// or an empty string when the commit was not pushed or the branch has no upstream.
+func (w *wizard) pushedTo(revision string) (string, error) {
+	upstream, err := w.git.Upstream()
+	if err != nil || upstream == "" {
+		return "", err
+	}
+	pushed, err := w.git.IsAncestor(revision, upstream)
+	if err != nil || !pushed {
+		return "", err
+	}
+	return upstream, nil
+}
+
+// keep asks whether to keep the values of a list read from the amended commit,
+// describing them as what. It reports true without asking when there are none.
+func (w *wizard) keep(what string, values []string) (bool, error) {
+	if len(values) == 0 {
+		return true, nil
+	}
+
+	keep, err := ui.ConfirmSelectDefault(w.prompter, fmt.Sprintf("Keep the %s (%s)?", what, strings.Join(values, "; ")), true)
+	if err != nil {
+		return false, fmt.Errorf("asking about the current %s: %w", what, err)
+	}
+	return keep, nil
+}
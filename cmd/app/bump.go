@@ -0,0 +1,125 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/bump"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/changelog"
+	cfg "github.com/GiulianoPoeta99/conventional_commits_cli/internal/config"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/git"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/semver"
+)
+
+// runBump implements `commit bump`.
+func runBump(g git.Git, args []string) error {
+	settings, err := cfg.Load()
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	fs := flag.NewFlagSet("commit bump", flag.ContinueOnError)
+	prefix := fs.String("prefix", settings.Bump.TagPrefix, "tag prefix before the optional 'v', e.g. api/ for api/v1.2.3")
+	path := fs.String("path", "", "only consider the commits touching this path (for monorepos)")
+	channel := fs.String("pre", "", "create a pre-release on this channel, e.g. rc or beta")
+	dryRun := fs.Bool("dry-run", false, "print the computed version and the commits that drove it")
+	tag := fs.Bool("tag", false, "create an annotated tag with the release notes")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: commit bump [flags]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Prints the next semantic version based on the commits since the latest release tag.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{message: err.Error()}
+	}
+	if fs.NArg() > 0 {
+		return newUsageError("unexpected argument %q", fs.Arg(0))
+	}
+
+	levels, err := bump.ParseLevels(settings.Bump.Levels)
+	if err != nil {
+		return fmt.Errorf("bump levels: %w", err)
+	}
+
+	// Find the latest release, the starting point of the calculation.
+	tags, err := g.Tags()
+	if err != nil {
+		return err
+	}
+	versions, latest, found := bump.Versions(tags, *prefix)
+	from := ""
+	if found {
+		from = latest.String()
+	} else {
+		latest = semver.Zero(*prefix)
+	}
+
+	paths := []string{}
+	if *path != "" {
+		paths = append(paths, *path)
+	}
+	commits, err := g.Log(from, "HEAD", paths...)
+	if err != nil {
+		return err
+	}
+
+	level, reasons := bump.Decide(commits, bump.Options{Types: settings.Types, Emojis: settings.Emojis, Levels: levels})
+	if level == semver.None {
+		fmt.Fprintf(os.Stderr, "No release needed: none of the %d commit(s) since %s triggers a version bump\n", len(commits), describeVersion(from))
+		return nil
+	}
+	next := bump.Next(latest, level, *channel, versions)
+
+	if *dryRun {
+		fmt.Printf("Current version: %s\n", describeVersion(from))
+		fmt.Printf("Next version:    %s (%s)\n", next, level)
+		fmt.Printf("\nCommits driving the bump (%d of %d):\n", len(reasons), len(commits))
+		for _, reason := range reasons {
+			marker := " "
+			if reason.Level == level {
+				marker = "*"
+			}
+			fmt.Printf("  %s %-5s %s %s\n", marker, reason.Level, reason.Commit.ShortHash(), reason.Commit.Header())
+		}
+		if *tag {
+			fmt.Printf("\nWould create the annotated tag %s\n", next)
+		}
+		return nil
+	}
+
+	if *tag {
+		release := changelog.Build(commits, changelog.Options{
+			Types:     settings.Types,
+			Emojis:    settings.Emojis,
+			Hidden:    settings.Changelog.Hidden,
+			IssueURL:  settings.Changelog.IssueURL,
+			CommitURL: settings.Changelog.CommitURL,
+		})
+		release.Version = next.String()
+		release.Date = time.Now().Format(time.DateOnly)
+
+		if err := g.CreateTag(next.String(), "Release "+next.String()+"\n\n"+changelog.Markdown(release)); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Created tag %s\n", next)
+	}
+
+	fmt.Println(next)
+	return nil
+}
+
+// describeVersion names the starting tag, or explains that there is none.
+func describeVersion(tag string) string {
+	if tag == "" {
+		return "(no release yet)"
+	}
+	return tag
+}
@@ -0,0 +1,21 @@
+package app
+
+import "testing"
+
+// TestBumpFirstRelease checks the tag of the first release, where the prefix gets a
+// "v" unless it already ends with one.
+func TestBumpFirstRelease(tt *testing.T) {
+	for prefix, want := range map[string]string{"": "v0.1.0", "v": "v0.1.0", "api/": "api/v0.1.0", "api/v": "api/v0.1.0"} {
+		tt.Run(prefix, func(tt *testing.T) {
+			g := testRepo(tt)
+			commitAll(tt, g, "feat: first feature")
+
+			if err := runBump(g, []string{"--prefix", prefix, "--tag"}); err != nil {
+				tt.Fatal(err)
+			}
+			if _, ok := g.TagTargets[want]; !ok || len(g.TagTargets) != 1 {
+				tt.Errorf("tags %v, want %s", g.TagTargets, want)
+			}
+		})
+	}
+}
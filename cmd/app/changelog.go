@@ -0,0 +1,146 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/changelog"
+	cfg "github.com/GiulianoPoeta99/conventional_commits_cli/internal/config"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/git"
+)
+
+// runChangelog implements `commit changelog`.
+func runChangelog(g git.Git, args []string) error {
+	fs := flag.NewFlagSet("commit changelog", flag.ContinueOnError)
+	from := fs.String("from", "", "start after this revision (default: the latest tag before --to)")
+	to := fs.String("to", "HEAD", "end at this revision")
+	version := fs.String("version", "", "section title (default: the tag at --to, or Unreleased)")
+	format := fs.String("format", "markdown", "output format: markdown or json")
+	templatePath := fs.String("template", "", "render with this Go text/template file instead of --format")
+	output := fs.String("output", "", "write to this file instead of stdout")
+	prepend := fs.Bool("prepend", false, "insert the section at the top of --output instead of overwriting it")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: commit changelog [flags]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Generates release notes from the Conventional Commits between two revisions.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{message: err.Error()}
+	}
+	if fs.NArg() > 0 {
+		return newUsageError("unexpected argument %q", fs.Arg(0))
+	}
+	if *format != "markdown" && *format != "json" {
+		return newUsageError("unknown format %q (expected markdown or json)", *format)
+	}
+	if *prepend && (*output == "" || *format != "markdown" || *templatePath != "") {
+		return newUsageError("--prepend requires --output and Markdown output")
+	}
+
+	settings, err := cfg.Load()
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	release, err := buildRelease(g, settings, *from, *to, *version)
+	if err != nil {
+		return err
+	}
+
+	// Render the release in the requested format.
+	var content string
+	switch {
+	case *templatePath != "":
+		content, err = changelog.Template(release, *templatePath)
+	case *format == "json":
+		content, err = changelog.JSON(release)
+	default:
+		content = changelog.Markdown(release)
+	}
+	if err != nil {
+		return err
+	}
+
+	if release.Skipped > 0 {
+		fmt.Fprintf(os.Stderr, "Skipped %d commit(s) that are not Conventional Commits\n", release.Skipped)
+	}
+	if release.Unknown > 0 {
+		fmt.Fprintf(os.Stderr, "Left out %d commit(s) whose type is not configured\n", release.Unknown)
+	}
+
+	switch {
+	case *prepend:
+		err = changelog.Prepend(*output, release.Version, content)
+		if errors.Is(err, changelog.ErrAlreadyReleased) {
+			fmt.Fprintf(os.Stderr, "%s already contains %s, nothing to do\n", *output, release.Version)
+			return nil
+		}
+		return err
+	case *output != "":
+		return os.WriteFile(*output, []byte(content), 0o644)
+	default:
+		fmt.Print(content)
+		return nil
+	}
+}
+
+// buildRelease collects the commits between from and to and groups them into a release.
+// An empty from means the latest tag before to; an empty version means the tag at to,
+// or Unreleased when to is not tagged.
+func buildRelease(g git.Git, settings cfg.Config, from, to, version string) (changelog.Release, error) {
+	if to == "" {
+		to = "HEAD"
+	}
+
+	tag := g.ExactTag(to)
+	if version == "" {
+		version = changelog.Unreleased
+		if tag != "" {
+			version = tag
+		}
+	}
+
+	// Start after the previous tag; when to is itself tagged, look before it.
+	if from == "" && tag != "" {
+		// A tagged root commit has no parent, hence no previous tag either.
+		from, _ = g.LatestTag(to + "^")
+	} else if from == "" {
+		var err error
+		from, err = g.LatestTag(to)
+		if err != nil {
+			return changelog.Release{}, err
+		}
+	}
+
+	commits, err := g.Log(from, to)
+	if err != nil {
+		return changelog.Release{}, err
+	}
+
+	release := changelog.Build(commits, changelog.Options{
+		Types:     settings.Types,
+		Emojis:    settings.Emojis,
+		Hidden:    settings.Changelog.Hidden,
+		IssueURL:  settings.Changelog.IssueURL,
+		CommitURL: settings.Changelog.CommitURL,
+	})
+	release.Version = version
+	release.From = from
+	release.To = to
+
+	// Date the release with its newest commit, or today when the range is empty.
+	release.Date = time.Now().Format(time.DateOnly)
+	if len(commits) > 0 && len(commits[0].Date) >= len(time.DateOnly) {
+		release.Date = commits[0].Date[:len(time.DateOnly)]
+	}
+
+	return release, nil
+}
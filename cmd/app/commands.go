@@ -0,0 +1,14 @@
+package app
+
+import "github.com/GiulianoPoeta99/conventional_commits_cli/internal/git"
+
+// commands maps subcommand names to their implementation.
+// Each one receives the repository to work on and the arguments that follow its name.
+var commands = map[string]func(g git.Git, args []string) error{
+	"lint":      runLint,
+	"hook":      runHook,
+	"changelog": runChangelog,
+	"bump":      runBump,
+	"scopes":    runScopes,
+	"fixup":     runFixup,
+}
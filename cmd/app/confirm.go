@@ -0,0 +1,393 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	commit "github.com/GiulianoPoeta99/conventional_commits_cli/internal"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/clipboard"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/editor"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/history"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/lint"
+	ui "github.com/GiulianoPoeta99/conventional_commits_cli/internal/ui"
+)
+
+// messageDraftName is the file under the git directory holding a message saved as a
+// draft, resumed by the next run of the wizard.
+const messageDraftName = "commit-draft.txt"
+
+// action is an entry of the confirmation menu.
+type action int
+
+const (
+	actionCommit action = iota
+	actionEditField
+	actionEditor
+	actionCopy
+	actionDraft
+	actionAbort
+)
+
+// field is a part of the message that the confirmation menu can ask again.
+type field struct {
+	name  string
+	value string
+	// flags are the flags skipping the prompts of the field, ignored once it is edited.
+	flags []string
+	steps []func() error
+}
+
+// confirm shows the message and asks what to do with it until it is committed, saved
+// as a draft or abandoned. A message rewritten in the editor is linted and must have
+// no errors to be committed, and a commit that fails comes back to the menu.
+func (w *wizard) confirm() error {
+	message := w.message()
+	edited := false
+
+	for {
+		commit.Preview(w.output(), message, w.settings.Message.HeaderMaxLength)
+		problems := []string{}
+		if edited {
+			problems = w.lintErrors(message)
+			for _, problem := range problems {
+				w.printf("❌ %s\n", problem)
+			}
+		}
+
+		chosen, err := w.selectAction()
+		if err != nil {
+			return err
+		}
+
+		switch chosen {
+		case actionCommit:
+			if len(problems) > 0 {
+				w.printf("The message must be fixed before committing.\n")
+				continue
+			}
+			// A failed commit, e.g. refused by a hook or unsigned, keeps the answers.
+			if err := commit.Commit(w.git, message, w.opts.commitOptions()); err != nil {
+				w.printf("❌ Committing: %v\n", err)
+				continue
+			}
+			return nil
+		case actionEditField:
+			changed, err := w.editField()
+			if err != nil {
+				return err
+			}
+			if changed || edited {
+				message, edited = w.message(), false
+			}
+		case actionEditor:
+			text, err := w.editMessage(message)
+			if err != nil {
+				w.printf("⚠️  Editing the message: %v\n", err)
+				continue
+			}
+			if text == message {
+				continue
+			}
+			message, edited = text, true
+
+			// The fields follow the edited message, for the scope history and the
+			// fields edited next, when they can be read back from it.
+			config, err := commit.ParseCommitMessage(message, w.settings.Types, w.settings.Emojis)
+			if err != nil {
+				w.printf("⚠️  The edited message cannot be read back (%v); editing a field starts from the previous answers.\n", err)
+				continue
+			}
+			w.config = config
+		case actionCopy:
+			if err := w.copyMessage(message); err != nil {
+				// Leaving the prompt stops the wizard like anywhere else.
+				if ui.IsInterrupt(err) {
+					return fmt.Errorf("copying the message: %w", err)
+				}
+				w.printf("⚠️  Copying the message: %v\n", err)
+			}
+		case actionDraft:
+			return w.saveDraft(message)
+		case actionAbort:
+			return errors.New("commit canceled by user")
+		}
+	}
+}
+
+// selectAction asks what to do with the message. The editor is offered when one is
+// configured, and drafts when the next run can resume them: amends and reverts are
+// started again by their own flags.
+func (w *wizard) selectAction() (action, error) {
+	actions := []action{actionCommit, actionEditField}
+	items := []string{"Commit", "Edit a field"}
+	if command, err := editor.Find(w.git); err == nil && command != "" {
+		actions = append(actions, actionEditor)
+		items = append(items, fmt.Sprintf("Edit the message in the editor (%s)", command))
+	}
+	actions = append(actions, actionCopy)
+	items = append(items, "Copy the message")
+	if !w.opts.Amend && w.revert == nil {
+		actions = append(actions, actionDraft)
+		items = append(items, "Save as a draft and quit")
+	}
+	actions = append(actions, actionAbort)
+	items = append(items, "Abort")
+
+	index, err := w.prompter.Select("What do you want to do with this message?", items, ui.SelectOptions{Size: len(items)})
+	if err != nil {
+		return 0, fmt.Errorf("confirming the commit: %w", err)
+	}
+	return actions[index], nil
+}
+
+// fields returns the fields of the message with their current values.
+func (w *wizard) fields() []field {
+	fields := []field{}
+	if w.revert == nil {
+		fields = append(fields, field{name: "type", value: w.config.Type.Code, flags: []string{"type"}, steps: []func() error{w.askType}})
+	}
+
+	emoji := ""
+	if w.config.Emoji.Code != "" {
+		emoji = w.config.Emoji.Symbol + " " + w.config.Emoji.Code
+	}
+	body, _, more := strings.Cut(w.config.Body, "\n")
+	if more {
+		body += " …"
+	}
+	breaking := "no"
+	if w.config.Breaking {
+		breaking = "yes"
+		if w.config.BreakingReason != "" {
+			breaking += ": " + w.config.BreakingReason
+		}
+	}
+
+	reviewers, coAuthors := []string{}, []string{}
+	reviewerFlags, coAuthorFlags := []string{}, []string{}
+	for _, role := range commit.Roles {
+		if role.Key == commit.CoAuthorKey {
+			coAuthorFlags = append(coAuthorFlags, role.Flag)
+		} else {
+			reviewerFlags = append(reviewerFlags, role.Flag)
+		}
+	}
+	for _, people := range commit.People(&w.config) {
+		if people.Key == commit.CoAuthorKey {
+			coAuthors = append(coAuthors, *people.Values...)
+		} else {
+			reviewers = append(reviewers, *people.Values...)
+		}
+	}
+	refs := []string{}
+	for _, ref := range w.config.ReferenceIssues {
+		refs = append(refs, ref.Issue)
+	}
+	trailers := []string{}
+	for _, footer := range w.config.Trailers {
+		trailers = append(trailers, footer.Key+": "+footer.Value)
+	}
+
+	return append(fields,
+		field{name: "scope", value: w.config.Scope, flags: []string{"scope"}, steps: []func() error{w.askScope}},
+		field{name: "emoji", value: emoji, flags: []string{"emoji"}, steps: []func() error{w.askEmoji}},
+		field{name: "description", value: w.config.Description, flags: []string{"description"}, steps: []func() error{w.askDescription}},
+		field{name: "body", value: body, flags: []string{"body"}, steps: []func() error{w.askBody}},
+		field{name: "breaking change", value: breaking, flags: []string{"breaking", "breaking-reason"}, steps: []func() error{w.askBreaking}},
+		field{name: "reviewers", value: strings.Join(reviewers, ", "), flags: reviewerFlags, steps: []func() error{w.askReviewers}},
+		field{name: "co-authors", value: strings.Join(coAuthors, ", "), flags: coAuthorFlags, steps: []func() error{w.askCoAuthors}},
+		field{name: "issue references", value: strings.Join(refs, ", "), flags: []string{"ref"}, steps: []func() error{w.askIssues}},
+		field{name: "trailers", value: strings.Join(trailers, "; "), flags: []string{"trailer"}, steps: []func() error{w.keepTrailers, w.signoff, w.askTrailers}},
+	)
+}
+
+// editField asks for a field and then asks its prompts again, starting from its
+// current value. It reports false when the user went back without editing.
+func (w *wizard) editField() (bool, error) {
+	fields := w.fields()
+	items := []string{}
+	for _, f := range fields {
+		value := f.value
+		if value == "" {
+			value = "(none)"
+		}
+		items = append(items, fmt.Sprintf("%-17s %s", f.name, value))
+	}
+	items = append(items, "Back")
+
+	index, err := w.prompter.Select("Which field do you want to edit?", items, ui.SelectOptions{Size: len(items)})
+	if err != nil {
+		return false, fmt.Errorf("selecting the field to edit: %w", err)
+	}
+	if index == len(fields) {
+		return false, nil
+	}
+
+	// The prompts of a field given by flags are skipped until it is edited.
+	f := fields[index]
+	for _, name := range f.flags {
+		delete(w.opts.set, name)
+	}
+	for _, step := range f.steps {
+		if err := step(); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// editMessage opens the whole message in the user's editor and returns it cleaned up
+// like git does.
+func (w *wizard) editMessage(message string) (string, error) {
+	command, err := editor.Find(w.git)
+	if err != nil {
+		return "", err
+	}
+
+	template := message + "\n\n" +
+		"# Edit the commit message. Lines starting with '#' are ignored, and the\n" +
+		"# message is checked like `commit lint` does before committing.\n"
+
+	e := &editor.Editor{Command: command}
+	if w.tty != nil {
+		e.Stdin, e.Stdout, e.Stderr = w.tty, w.tty, w.tty
+	}
+	content, err := e.Edit(template)
+	if err != nil {
+		return "", err
+	}
+	return editor.Cleanup(content), nil
+}
+
+// copyMessage copies the message to the clipboard, a file or the standard output.
+func (w *wizard) copyMessage(message string) error {
+	tool := clipboard.Find()
+	items := []string{"To a file", "To the standard output", "Back"}
+	if tool != nil {
+		items = append([]string{"To the clipboard (" + tool[0] + ")"}, items...)
+	}
+
+	index, err := w.prompter.Select("Where do you want to copy the message?", items, ui.SelectOptions{Size: len(items)})
+	if err != nil {
+		return err
+	}
+	if tool == nil {
+		index++
+	}
+
+	switch index {
+	case 0:
+		if err := clipboard.Copy(tool, message); err != nil {
+			return err
+		}
+		w.printf("📋 Copied the message to the clipboard\n")
+	case 1:
+		path, err := ui.InputWithValidation(w.prompter, "Path of the file", "", validatePath)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(message+"\n"), 0o644); err != nil {
+			return err
+		}
+		w.printf("📋 Wrote the message to %s\n", path)
+	case 2:
+		fmt.Println(message)
+	}
+	return nil
+}
+
+// validatePath accepts any path that is not blank.
+func validatePath(input string) error {
+	if strings.TrimSpace(input) == "" {
+		return errors.New("path cannot be empty")
+	}
+	return nil
+}
+
+// saveDraft writes the message under the git directory for the next run of the wizard
+// and stops without committing.
+func (w *wizard) saveDraft(message string) error {
+	path, err := w.git.GitPath(messageDraftName)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(message+"\n"), 0o644); err != nil {
+		return fmt.Errorf("saving the draft: %w", err)
+	}
+	w.printf("📝 Draft saved to %s; run commit again to resume it\n", path)
+	return &exitError{code: 0}
+}
+
+// resumeDraft offers to start from the message saved as a draft by a previous run.
+// A declined draft is discarded.
+func (w *wizard) resumeDraft() error {
+	if !w.interactive {
+		return nil
+	}
+	path, err := w.git.GitPath(messageDraftName)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	message := strings.TrimSpace(string(content))
+	header, _, _ := strings.Cut(message, "\n")
+	saved := ""
+	if info, err := os.Stat(path); err == nil {
+		saved = " " + history.Ago(info.ModTime(), time.Now())
+	}
+	resume, err := ui.ConfirmSelectDefault(w.prompter, fmt.Sprintf("Resume the draft saved%s (%s)?", saved, header), true)
+	if err != nil {
+		return fmt.Errorf("asking about the draft: %w", err)
+	}
+	if !resume {
+		return os.Remove(path)
+	}
+
+	// Drafts that are not Conventional Commits still pre-fill what could be read.
+	w.config, _ = commit.ParseCommitMessage(message, w.settings.Types, w.settings.Emojis)
+	w.draft = true
+	return nil
+}
+
+// finishDraft forgets the resumed draft once it is committed.
+func (w *wizard) finishDraft() error {
+	if !w.draft {
+		return nil
+	}
+	path, err := w.git.GitPath(messageDraftName)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// lintErrors returns the errors the linter finds in message.
+func (w *wizard) lintErrors(message string) []string {
+	diagnostics := lint.Lint(lint.CleanMessage(message, lint.DefaultCommentChar), lint.Options{
+		Types:           w.settings.Types,
+		Emojis:          w.settings.Emojis,
+		HeaderMaxLength: w.settings.Message.HeaderMaxLength,
+		Trailers:        w.settings.Trailers,
+	})
+
+	problems := []string{}
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == lint.SeverityError {
+			problems = append(problems, diagnostic.Message)
+		}
+	}
+	return problems
+}
@@ -0,0 +1,268 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	commit "github.com/GiulianoPoeta99/conventional_commits_cli/internal"
+	cfg "github.com/GiulianoPoeta99/conventional_commits_cli/internal/config"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/git"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/history"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/lint"
+	ui "github.com/GiulianoPoeta99/conventional_commits_cli/internal/ui"
+)
+
+// fixupCandidates is the number of recent commits searched for fixup targets.
+const fixupCandidates = 50
+
+// runFixup implements `commit fixup`.
+func runFixup(g git.Git, args []string) error {
+	settings, err := cfg.Load()
+	if err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	fs := flag.NewFlagSet("commit fixup", flag.ContinueOnError)
+	target := fs.String("target", "", "commit to fix up, instead of picking it from the recent ones")
+	squash := fs.Bool("squash", false, "create a squash! commit, adding --message to the message of the target")
+	amend := fs.Bool("amend", false, "create an amend! commit, replacing the message of the target with --message")
+	message := fs.String("message", "", "text added by --squash, or new message given by --amend")
+	autosquash := fs.Bool("autosquash", false, "fold the new commit into its target with git rebase --autosquash")
+	force := fs.Bool("force", false, "with --autosquash, rewrite a target already pushed upstream")
+	answers := fs.String("answers", "", "replay the prompts from a YAML or JSON answers script")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: commit fixup [flags]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Records the staged changes as a fixup!, squash! or amend! commit of an earlier")
+		fmt.Fprintln(fs.Output(), "commit, which git rebase --autosquash folds into it.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{message: err.Error()}
+	}
+	if fs.NArg() > 0 {
+		return newUsageError("unexpected argument %q", fs.Arg(0))
+	}
+	if *squash && *amend {
+		return newUsageError("--squash and --amend cannot be combined")
+	}
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	w := &wizard{git: g, prompter: ui.NewPromptui(), opts: options{set: map[string]bool{}}, interactive: ui.IsInteractive(), settings: settings}
+	var script *ui.Scripted
+	if *answers != "" {
+		script, err = ui.LoadScript(*answers)
+		if err != nil {
+			return fmt.Errorf("loading answers: %w", err)
+		}
+		script.Transcript = os.Stdout
+		w.prompter = script
+		w.interactive = true
+	}
+
+	c, err := w.fixupTarget(*target)
+	if err != nil {
+		return err
+	}
+
+	// Folding into a published commit rewrites history, so it must be asked for.
+	upstream, err := w.pushedTo(c.Hash)
+	if err != nil {
+		return err
+	}
+	if *autosquash && upstream != "" && !*force {
+		return fmt.Errorf("%s was already pushed to %s; folding into it rewrites published history (use --force to rebase anyway)", c.ShortHash(), upstream)
+	}
+
+	kind := commit.FixupPlain
+	switch {
+	case *squash:
+		kind = commit.FixupSquash
+	case *amend:
+		kind = commit.FixupAmend
+	case w.interactive:
+		if kind, err = w.fixupKind(); err != nil {
+			return err
+		}
+	}
+
+	// Only amend! may reword the target without changes.
+	staged, err := g.HasStagedChanges()
+	if err != nil {
+		return err
+	}
+	if !staged && kind != commit.FixupAmend {
+		return fmt.Errorf("no staged changes to fold into %s. Use 'git add' first", c.ShortHash())
+	}
+
+	text, err := w.fixupText(kind, c, set["message"], *message)
+	if err != nil {
+		return err
+	}
+	if err := commit.Commit(g, commit.FixupMessage(kind, c.Header(), text), git.CommitOptions{AllowEmpty: kind == commit.FixupAmend}); err != nil {
+		return fmt.Errorf("committing: %w", err)
+	}
+	fmt.Printf("✅ Created %s! commit for %s\n", kind, c.ShortHash())
+
+	fold := *autosquash
+	if !set["autosquash"] && w.interactive {
+		label := fmt.Sprintf("Fold it into %s now with git rebase --autosquash?", c.ShortHash())
+		if upstream != "" {
+			label = fmt.Sprintf("Fold it into %s now with git rebase --autosquash? %s was already pushed to %s, rebasing rewrites published history", c.ShortHash(), c.ShortHash(), upstream)
+		}
+		if fold, err = ui.ConfirmSelect(w.prompter, label); err != nil {
+			return fmt.Errorf("asking about the autosquash: %w", err)
+		}
+	}
+	if fold {
+		if err := g.Autosquash(c.Hash); err != nil {
+			return fmt.Errorf("%w (resolve the conflicts and run git rebase --continue, or git rebase --abort)", err)
+		}
+		fmt.Printf("✅ Folded into %s %s\n", c.ShortHash(), c.Header())
+	}
+
+	if script != nil && script.Remaining() > 0 {
+		return fmt.Errorf("answers script has %d unused answers", script.Remaining())
+	}
+	return nil
+}
+
+// fixupTarget returns the commit at revision, or asks for one of the recent Conventional
+// Commits of the current branch.
+func (w *wizard) fixupTarget(revision string) (git.Commit, error) {
+	if revision != "" {
+		c, err := w.git.CommitAt(revision)
+		if err != nil {
+			return git.Commit{}, newUsageError("--target: %v", err)
+		}
+		return c, nil
+	}
+	if !w.interactive {
+		return git.Commit{}, newUsageError("missing required flag: --target (stdin is not a terminal, prompts are disabled)")
+	}
+
+	recent, err := w.git.Recent(fixupCandidates)
+	if err != nil {
+		return git.Commit{}, err
+	}
+
+	// The commits missing from the upstream branch are the ones safe to rewrite.
+	unpushed := map[string]bool{}
+	upstream, err := w.git.Upstream()
+	if err != nil {
+		return git.Commit{}, err
+	}
+	if upstream != "" {
+		commits, err := w.git.Log(upstream, "")
+		if err != nil {
+			return git.Commit{}, err
+		}
+		for _, c := range commits {
+			unpushed[c.Hash] = true
+		}
+	}
+
+	now := time.Now()
+	candidates := []git.Commit{}
+	items := []string{}
+	for _, c := range recent {
+		if lint.IsIgnored(c.Message) {
+			continue
+		}
+		if _, err := commit.ParseCommitMessage(c.Message, w.settings.Types, w.settings.Emojis); err != nil {
+			continue
+		}
+
+		detail := c.Date
+		if date, err := time.Parse(time.RFC3339, c.Date); err == nil {
+			detail = history.Ago(date, now)
+		}
+		if upstream != "" && !unpushed[c.Hash] {
+			detail += ", pushed"
+		}
+		candidates = append(candidates, c)
+		items = append(items, fmt.Sprintf("%s %s (%s)", c.ShortHash(), c.Header(), detail))
+	}
+	if len(candidates) == 0 {
+		return git.Commit{}, fmt.Errorf("no Conventional Commits to fix up among the last %d commits", fixupCandidates)
+	}
+
+	index, err := w.prompter.Select("Select the commit to fix up (type / to search by header or hash)", items, ui.SelectOptions{
+		Size: min(len(items), 10),
+		Searcher: func(input string, index int) bool {
+			input = strings.ToLower(strings.TrimSpace(input))
+			return strings.Contains(strings.ToLower(items[index]), input) || strings.HasPrefix(candidates[index].Hash, input)
+		},
+	})
+	if err != nil {
+		return git.Commit{}, fmt.Errorf("selecting the commit to fix up: %w", err)
+	}
+	return candidates[index], nil
+}
+
+// fixupKind asks how the staged changes are folded into the target.
+func (w *wizard) fixupKind() (commit.Fixup, error) {
+	kinds := []commit.Fixup{commit.FixupPlain, commit.FixupSquash, commit.FixupAmend}
+	items := []string{
+		"fixup!  fold the changes in, keeping its message",
+		"squash! fold the changes in and add a note to its message",
+		"amend!  fold the changes in and replace its message",
+	}
+
+	index, err := w.prompter.Select("How should it be folded into the commit?", items, ui.SelectOptions{Size: len(items)})
+	if err != nil {
+		return "", fmt.Errorf("selecting the kind of fixup: %w", err)
+	}
+	return kinds[index], nil
+}
+
+// fixupText returns the text that a commit of the given kind adds to the message of
+// the target: the note of squash!, or the new message of amend!, which is written
+// with the wizard starting from the message of the target.
+func (w *wizard) fixupText(kind commit.Fixup, target git.Commit, given bool, message string) (string, error) {
+	switch kind {
+	case commit.FixupSquash:
+		if given || !w.interactive {
+			return message, nil
+		}
+		text, err := ui.OptionalInput(w.prompter, "Text to add to its message when squashing (optional, press Enter to omit)")
+		if err != nil {
+			return "", fmt.Errorf("entering the squash text: %w", err)
+		}
+		return text, nil
+	case commit.FixupAmend:
+		if given {
+			return message, w.lintFixupMessage(message)
+		}
+		if !w.interactive {
+			return "", newUsageError("missing required flag: --message with the new message of %s (stdin is not a terminal, prompts are disabled)", target.ShortHash())
+		}
+
+		config, _ := commit.ParseCommitMessage(target.Message, w.settings.Types, w.settings.Emojis)
+		w.config = config
+		w.amended = &config
+		if err := w.ask(); err != nil {
+			return "", err
+		}
+		return w.message(), nil
+	}
+	return "", nil
+}
+
+// lintFixupMessage rejects a new message given to amend! that the linter would reject
+// once autosquashed.
+func (w *wizard) lintFixupMessage(message string) error {
+	if problems := w.lintErrors(message); len(problems) > 0 {
+		return newUsageError("--message: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
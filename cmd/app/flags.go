@@ -0,0 +1,240 @@
+// Package app wires the command-line interface of the Conventional Commits Assistant.
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	commit "github.com/GiulianoPoeta99/conventional_commits_cli/internal"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/git"
+)
+
+// usageError reports invalid or missing command-line arguments.
+// It makes the program exit with status 2 instead of 1.
+type usageError struct {
+	message string
+}
+
+// Error returns the usage error message.
+func (e *usageError) Error() string {
+	return e.message
+}
+
+// newUsageError builds a usageError from a format string.
+func newUsageError(format string, args ...any) error {
+	return &usageError{message: fmt.Sprintf(format, args...)}
+}
+
+// exitError makes the program exit with the given status.
+// When err is nil nothing is printed, because the command already reported the outcome.
+type exitError struct {
+	code int
+	err  error
+}
+
+// Error returns the wrapped error message or the exit status.
+func (e *exitError) Error() string {
+	if e.err != nil {
+		return e.err.Error()
+	}
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+// Unwrap returns the wrapped error.
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// exitCode returns the process status matching err.
+func exitCode(err error) int {
+	var usage *usageError
+	if errors.As(err, &usage) {
+		return 2
+	}
+	var exit *exitError
+	if errors.As(err, &exit) {
+		return exit.code
+	}
+	return 1
+}
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+// String returns the values joined by commas.
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+// Set appends a value each time the flag is given.
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// signFlag is the --gpg-sign flag. Given alone it signs with the default key, while
+// --gpg-sign=<key> names the key and --gpg-sign=false turns signing off, as with git.
+type signFlag struct {
+	sign bool
+	key  string
+}
+
+// String returns the key, or whether to sign when no key is given.
+func (f *signFlag) String() string {
+	if f.key != "" {
+		return f.key
+	}
+	return strconv.FormatBool(f.sign)
+}
+
+// Set reads a boolean, or else a key implying signing.
+func (f *signFlag) Set(value string) error {
+	if sign, err := strconv.ParseBool(value); err == nil {
+		f.sign, f.key = sign, ""
+		return nil
+	}
+	f.sign, f.key = true, value
+	return nil
+}
+
+// IsBoolFlag lets the flag be given without a value.
+func (f *signFlag) IsBoolFlag() bool {
+	return true
+}
+
+// options holds the values given on the command line for the wizard.
+type options struct {
+	Type           string
+	Scope          string
+	Emoji          string
+	Description    string
+	Body           string
+	Editor         bool
+	Breaking       bool
+	BreakingReason string
+	People         map[string]*stringList // values of the people flags by trailer key
+	Refs           stringList
+	Trailers       stringList
+	Signoff        bool
+	Sign           signFlag
+	Amend          bool
+	Force          bool
+	Reverts        stringList
+	Stage          bool
+	Yes            bool
+	Answers        string
+
+	// set records the flags explicitly present on the command line.
+	set map[string]bool
+}
+
+// commitOptions returns how git must record the commit: signing follows the git
+// configuration unless --gpg-sign or -S was given.
+func (o options) commitOptions() git.CommitOptions {
+	opts := git.CommitOptions{Amend: o.Amend}
+	if o.has("gpg-sign") || o.has("S") {
+		sign := o.Sign.sign
+		opts.Sign, opts.SignKey = &sign, o.Sign.key
+	}
+	return opts
+}
+
+// has reports whether the named flag was given on the command line.
+func (o options) has(name string) bool {
+	return o.set[name]
+}
+
+// parseOptions parses the wizard flags from args (without the program name).
+func parseOptions(args []string) (options, error) {
+	opts := options{set: map[string]bool{}, People: map[string]*stringList{}}
+
+	fs := flag.NewFlagSet("commit", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.StringVar(&opts.Type, "type", "", "commit type code (e.g. feat, fix)")
+	fs.StringVar(&opts.Scope, "scope", "", "scope of the change")
+	fs.StringVar(&opts.Emoji, "emoji", "", "emoji code, with or without colons (e.g. sparkles)")
+	fs.StringVar(&opts.Description, "description", "", "short description of the change")
+	fs.StringVar(&opts.Body, "body", "", "commit body")
+	fs.BoolVar(&opts.Editor, "editor", false, "write the body in $GIT_EDITOR, core.editor, $VISUAL or $EDITOR")
+	fs.BoolVar(&opts.Breaking, "breaking", false, "mark the commit as a breaking change")
+	fs.StringVar(&opts.BreakingReason, "breaking-reason", "", "explanation for the BREAKING CHANGE footer (implies --breaking)")
+	for _, role := range commit.Roles {
+		opts.People[role.Key] = &stringList{}
+		fs.Var(opts.People[role.Key], role.Flag, fmt.Sprintf("person who %s, credited as %s: 'Name <email>' or the exact name or email of a known person (repeatable)", role.Description, role.Key))
+	}
+	fs.Var(&opts.Refs, "ref", "issue reference, e.g. #123 or PROJ-123, optionally with its relation as in closes=#123 (repeatable)")
+	fs.Var(&opts.Trailers, "trailer", "other trailer, as Key=value or 'Key: value' (repeatable)")
+	fs.BoolVar(&opts.Signoff, "signoff", false, "add a Signed-off-by trailer with user.name and user.email (default from message.signoff)")
+	fs.Var(&opts.Sign, "gpg-sign", "sign the commit, with the default key or the one given as --gpg-sign=<key> or -S<key>; --gpg-sign=false disables commit.gpgSign")
+	fs.Var(&opts.Sign, "S", "shorthand for --gpg-sign")
+	fs.BoolVar(&opts.Amend, "amend", false, "re-open the last commit in the wizard and amend it")
+	fs.BoolVar(&opts.Force, "force", false, "with --amend, amend even a commit already pushed upstream")
+	fs.Var(&opts.Reverts, "revert", "commit to revert, implies --type revert (repeatable)")
+	fs.BoolVar(&opts.Stage, "stage", false, "pick files to stage before the first prompt, even when some are staged")
+	fs.BoolVar(&opts.Yes, "yes", false, "commit without asking for confirmation")
+	fs.StringVar(&opts.Answers, "answers", "", "replay the prompts from a YAML or JSON answers script")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: commit [flags]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Every flag skips the matching prompt. When stdin is not a terminal,")
+		fmt.Fprintln(fs.Output(), "--type and --description are required and no prompt is shown.")
+		fmt.Fprintln(fs.Output(), "With --amend the prompts start from the message of the last commit.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(gluedSignArgs(fs, args)); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return opts, err
+		}
+		return opts, &usageError{message: err.Error()}
+	}
+	if fs.NArg() > 0 {
+		return opts, newUsageError("unexpected argument %q", fs.Arg(0))
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		opts.set[f.Name] = true
+	})
+
+	return opts, nil
+}
+
+// gluedSignArgs rewrites the -S<key> form of git, which the flag package would take for
+// an unknown flag, into --gpg-sign=<key>. Values of the flags of fs are left alone.
+func gluedSignArgs(fs *flag.FlagSet, args []string) []string {
+	rewritten := make([]string, 0, len(args))
+	for i, arg := range args {
+		if arg == "--" {
+			return append(rewritten, args[i:]...)
+		}
+
+		if key, ok := strings.CutPrefix(arg, "-S"); ok && key != "" && key[0] != '=' && !takesValue(fs, rewritten) {
+			arg = "--gpg-sign=" + key
+		}
+		rewritten = append(rewritten, arg)
+	}
+	return rewritten
+}
+
+// takesValue reports whether the last of args is a flag of fs expecting its value in
+// the next argument.
+func takesValue(fs *flag.FlagSet, args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	name, ok := strings.CutPrefix(args[len(args)-1], "-")
+	if !ok || strings.Contains(name, "=") {
+		return false
+	}
+	f := fs.Lookup(strings.TrimPrefix(name, "-"))
+	if f == nil {
+		return false
+	}
+	boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return !ok || !boolFlag.IsBoolFlag()
+}
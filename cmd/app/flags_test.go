@@ -0,0 +1,35 @@
+package app
+
+import "testing"
+
+// TestParseOptionsSign checks the forms of the signing flag, including the -S<key> form
+// of git.
+func TestParseOptionsSign(tt *testing.T) {
+	tests := []struct {
+		args        []string
+		sign        bool
+		key         string
+		description string
+	}{
+		{args: []string{"-S"}, sign: true},
+		{args: []string{"--gpg-sign=ABCD1234"}, sign: true, key: "ABCD1234"},
+		{args: []string{"-SABCD1234"}, sign: true, key: "ABCD1234"},
+		{args: []string{"-S=ABCD1234"}, sign: true, key: "ABCD1234"},
+		{args: []string{"--gpg-sign=false"}},
+		{args: []string{"--description", "-Sfoo"}, description: "-Sfoo"},
+		{args: []string{"--description=x", "-Sfoo"}, sign: true, key: "foo", description: "x"},
+		{args: []string{"--yes", "-Sfoo"}, sign: true, key: "foo"},
+	}
+
+	for _, test := range tests {
+		opts, err := parseOptions(test.args)
+		if err != nil {
+			tt.Errorf("parseOptions(%q): %v", test.args, err)
+			continue
+		}
+		if opts.Sign.sign != test.sign || opts.Sign.key != test.key || opts.Description != test.description {
+			tt.Errorf("parseOptions(%q) signs %v with %q and describes %q, want %v with %q and %q",
+				test.args, opts.Sign.sign, opts.Sign.key, opts.Description, test.sign, test.key, test.description)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/git"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/hook"
+	ui "github.com/GiulianoPoeta99/conventional_commits_cli/internal/ui"
+)
+
+// runHook implements `commit hook install|uninstall|status` and the internal
+// `commit hook run <hook>` entry point called by the installed scripts.
+func runHook(g git.Git, args []string) error {
+	fs := flag.NewFlagSet("commit hook", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: commit hook install|uninstall|status")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Manages the commit-msg and prepare-commit-msg hooks that lint messages")
+		fmt.Fprintln(fs.Output(), "and run the assistant from plain `git commit`.")
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{message: err.Error()}
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return newUsageError("missing hook action")
+	}
+
+	action := fs.Arg(0)
+	if action == "run" {
+		if fs.NArg() < 3 || fs.Arg(1) != "prepare-commit-msg" {
+			return newUsageError("usage: commit hook run prepare-commit-msg <file> [source [sha]]")
+		}
+		return runPrepareCommitMsg(g, fs.Args()[2:])
+	}
+
+	dir, err := g.HooksDir()
+	if err != nil {
+		return err
+	}
+
+	var statuses []hook.Status
+	switch action {
+	case "install":
+		executable, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("locating the commit executable: %w", err)
+		}
+		if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+			executable = resolved
+		}
+		statuses, err = hook.Install(dir, executable)
+		if err != nil {
+			return err
+		}
+	case "uninstall":
+		statuses, err = hook.Uninstall(dir)
+		if err != nil {
+			return err
+		}
+	case "status":
+		statuses = hook.Inspect(dir)
+	default:
+		return newUsageError("unknown hook action %q (expected install, uninstall or status)", action)
+	}
+
+	fmt.Printf("Hooks directory: %s\n", dir)
+	for _, status := range statuses {
+		line := fmt.Sprintf("  %-20s %s", status.Name, status.State)
+		if status.Chained != "" {
+			line += fmt.Sprintf(" (chains %s)", filepath.Base(status.Chained))
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// runPrepareCommitMsg runs the wizard from the prepare-commit-msg hook and writes the
+// resulting message into the file git is about to open in the editor.
+// args are the hook arguments: the message file, and optionally its source and sha.
+func runPrepareCommitMsg(g git.Git, args []string) error {
+	// Leave messages coming from -m, -F, templates, merges, squashes and amends untouched.
+	if len(args) > 1 && args[1] != "" {
+		return nil
+	}
+
+	// Without a terminal (e.g. an IDE) fall back silently to the regular editor flow.
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil
+	}
+	defer tty.Close()
+
+	fmt.Fprintln(tty, "🚀 Conventional Commits Assistant")
+
+	w := &wizard{
+		git:         g,
+		prompter:    &ui.Promptui{Stdin: tty, Stdout: tty},
+		tty:         tty,
+		opts:        options{set: map[string]bool{}},
+		interactive: true,
+	}
+	if err := w.collect(); err != nil {
+		// An interrupted wizard keeps git's default message.
+		if ui.IsInterrupt(err) {
+			return nil
+		}
+		return err
+	}
+
+	return hook.WriteMessage(args[0], w.message())
+}
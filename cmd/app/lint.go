@@ -0,0 +1,171 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	cfg "github.com/GiulianoPoeta99/conventional_commits_cli/internal/config"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/git"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/lint"
+)
+
+// lintResult groups the diagnostics of one linted message.
+type lintResult struct {
+	Source      string            `json:"source"`
+	Header      string            `json:"header"`
+	Diagnostics []lint.Diagnostic `json:"diagnostics"`
+}
+
+// runLint implements `commit lint`.
+// It exits with status 0 when every message is valid, 1 when errors were found,
+// and 2 when the messages could not be read.
+func runLint(g git.Git, args []string) error {
+	fs := flag.NewFlagSet("commit lint", flag.ContinueOnError)
+	from := fs.String("from", "", "lint the commits after this revision")
+	to := fs.String("to", "", "lint the commits up to this revision (default HEAD)")
+	format := fs.String("format", "text", "output format: text or json")
+	quiet := fs.Bool("quiet", false, "print nothing when no problem is found")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: commit lint [flags] [file | -]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Lints the message in file (as passed to the commit-msg hook), stdin,")
+		fmt.Fprintln(fs.Output(), "or every commit in the --from/--to range.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{message: err.Error()}
+	}
+	if *format != "text" && *format != "json" {
+		return newUsageError("unknown format %q (expected text or json)", *format)
+	}
+	if fs.NArg() > 1 {
+		return newUsageError("expected at most one file, got %d", fs.NArg())
+	}
+
+	settings, err := cfg.Load()
+	if err != nil {
+		return &exitError{code: 2, err: fmt.Errorf("loading configuration: %w", err)}
+	}
+	opts := lint.Options{
+		Types:           settings.Types,
+		Emojis:          settings.Emojis,
+		HeaderMaxLength: settings.Message.HeaderMaxLength,
+		Trailers:        settings.Trailers,
+	}
+
+	// Gather the messages to lint, from the revision range or a single file.
+	results := []lintResult{}
+	if *from != "" || *to != "" {
+		if fs.NArg() > 0 {
+			return newUsageError("a file cannot be combined with --from/--to")
+		}
+
+		commits, err := g.Log(*from, *to)
+		if err != nil {
+			return &exitError{code: 2, err: err}
+		}
+		for _, c := range commits {
+			results = append(results, lintResult{Source: c.ShortHash(), Header: c.Header(), Diagnostics: lint.Lint(c.Message, opts)})
+		}
+	} else {
+		source := fs.Arg(0)
+		message, err := readMessage(source)
+		if err != nil {
+			return &exitError{code: 2, err: err}
+		}
+		if source == "" {
+			source = "-"
+		}
+
+		message = lint.CleanMessage(message, commentChar(g))
+		header, _, _ := strings.Cut(message, "\n")
+		results = append(results, lintResult{Source: source, Header: header, Diagnostics: lint.Lint(message, opts)})
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printLintResults(results, *quiet)
+	}
+
+	for _, result := range results {
+		if lint.HasErrors(result.Diagnostics) {
+			return &exitError{code: 1}
+		}
+	}
+	return nil
+}
+
+// commentChar returns the prefix of the comment lines git leaves in the message file
+// passed to the commit-msg hook, from core.commentChar. With "auto" git picks a
+// character the message does not use, which cannot be told afterwards, so the
+// default is assumed.
+func commentChar(g git.Git) string {
+	value, err := g.Config("core.commentChar")
+	if err != nil || value == "" || value == "auto" {
+		return lint.DefaultCommentChar
+	}
+	return value
+}
+
+// readMessage reads a commit message from path, or from stdin when path is empty or "-".
+func readMessage(path string) (string, error) {
+	if path == "" || path == "-" {
+		content, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("reading stdin: %w", err)
+		}
+		return string(content), nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// printLintResults prints the diagnostics in a human-readable form followed by a summary.
+// In quiet mode the summary is omitted when no problem was found.
+func printLintResults(results []lintResult, quiet bool) {
+	errorCount, warningCount := 0, 0
+
+	for _, result := range results {
+		if len(result.Diagnostics) == 0 {
+			continue
+		}
+
+		fmt.Printf("%s: %s\n", result.Source, result.Header)
+		for _, diagnostic := range result.Diagnostics {
+			fmt.Printf("  %s\n", diagnostic)
+			if diagnostic.Severity == lint.SeverityError {
+				errorCount++
+			} else {
+				warningCount++
+			}
+		}
+	}
+
+	if errorCount+warningCount == 0 {
+		if quiet {
+			return
+		}
+		fmt.Printf("✔ %d message(s) checked, no problems found\n", len(results))
+		return
+	}
+	fmt.Printf("✖ %d problem(s) (%d error(s), %d warning(s)) in %d message(s)\n",
+		errorCount+warningCount, errorCount, warningCount, len(results))
+}
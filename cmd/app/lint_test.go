@@ -0,0 +1,73 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLintExitCodes checks the status of `commit lint` for valid, invalid and unreadable
+// messages, given as a file or as a revision range.
+func TestLintExitCodes(tt *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		history  []string
+		args     []string
+		want     int
+		settings map[string]string
+	}{
+		{name: "valid file", message: "feat: add the endpoint\n# a comment\n", want: 0},
+		{name: "warning only", message: "feat: add the endpoint.\n", want: 0},
+		{name: "invalid file", message: "feature: add the endpoint\n", want: 1},
+		{name: "missing file", args: []string{"missing.txt"}, want: 2},
+		{
+			name:     "comment char",
+			message:  "feat: add the endpoint\n; feature: not a header\n",
+			settings: map[string]string{"core.commentChar": ";"},
+			want:     0,
+		},
+		{
+			name:    "valid range",
+			history: []string{"feat: add the endpoint", "fixup! feat: add the endpoint", "fix: handle the timeout"},
+			args:    []string{"--to", "HEAD"},
+			want:    0,
+		},
+		{
+			name:    "invalid range",
+			history: []string{"feat: add the endpoint", "oops"},
+			args:    []string{"--to", "HEAD"},
+			want:    1,
+		},
+		{name: "unknown revision", args: []string{"--from", "v9.9.9"}, want: 2},
+		{name: "file and range", message: "feat: add the endpoint\n", args: []string{"--to", "HEAD"}, want: 2},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			g := testRepo(tt)
+			commitAll(tt, g, test.history...)
+			for key, value := range test.settings {
+				g.Settings[key] = value
+			}
+
+			args := append([]string{"--quiet"}, test.args...)
+			if test.message != "" {
+				path := filepath.Join(tt.TempDir(), "COMMIT_EDITMSG")
+				if err := os.WriteFile(path, []byte(test.message), 0o644); err != nil {
+					tt.Fatal(err)
+				}
+				args = append(args, path)
+			}
+
+			err := runLint(g, args)
+			status := 0
+			if err != nil {
+				status = exitCode(err)
+			}
+			if status != test.want {
+				tt.Errorf("runLint(%q) = %v (status %d), want status %d", args, err, status, test.want)
+			}
+		})
+	}
+}
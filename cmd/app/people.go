@@ -0,0 +1,275 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	commit "github.com/GiulianoPoeta99/conventional_commits_cli/internal"
+	cfg "github.com/GiulianoPoeta99/conventional_commits_cli/internal/config"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/history"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/identity"
+	ui "github.com/GiulianoPoeta99/conventional_commits_cli/internal/ui"
+)
+
+// person is an identity proposed by the people pickers, with where it comes from.
+type person struct {
+	id     identity.Identity
+	detail string
+}
+
+// askReviewers collects the reviewers and the people credited with Acked-by, Tested-by
+// and Reported-by, from the matching flags or a prompt loop.
+func (w *wizard) askReviewers() error {
+	roles, keys := []commit.Role{}, []string{}
+	for _, role := range commit.Roles {
+		if role.Key != commit.CoAuthorKey {
+			roles, keys = append(roles, role), append(keys, role.Key)
+		}
+	}
+
+	given, err := w.peopleFromFlags(keys)
+	if err != nil || given || !w.interactive {
+		return err
+	}
+	if err := w.keepPeople("reviewers and acknowledgements", keys); err != nil {
+		return err
+	}
+
+	// Confirm whether the user wants to credit anyone.
+	add, err := ui.ConfirmSelect(w.prompter, "Do you want to add reviewers or other acknowledgements?")
+	if err != nil {
+		return fmt.Errorf("asking about reviewers: %w", err)
+	}
+
+	items := []string{}
+	for _, role := range roles {
+		items = append(items, fmt.Sprintf("%-12s %s", role.Key, role.Description))
+	}
+
+	for add {
+		index, err := w.prompter.Select("How was this person involved?", items, ui.SelectOptions{Size: len(items)})
+		if err != nil {
+			return fmt.Errorf("selecting acknowledgement: %w", err)
+		}
+		role := roles[index]
+
+		if _, err := w.pickPerson(role.Key, fmt.Sprintf("Select who %s", role.Description)); err != nil {
+			return fmt.Errorf("selecting %s: %w", role.Key, err)
+		}
+
+		// Stop asking if nobody else is to be credited.
+		add, err = ui.ConfirmSelect(w.prompter, "Do you want to add another reviewer or acknowledgement?")
+		if err != nil {
+			return fmt.Errorf("asking about more reviewers: %w", err)
+		}
+	}
+	return nil
+}
+
+// askCoAuthors collects co-authors from the --co-author flags or a prompt loop.
+func (w *wizard) askCoAuthors() error {
+	given, err := w.peopleFromFlags([]string{commit.CoAuthorKey})
+	if err != nil || given || !w.interactive {
+		return err
+	}
+	if err := w.keepPeople("co-authors", []string{commit.CoAuthorKey}); err != nil {
+		return err
+	}
+
+	add, err := ui.ConfirmSelect(w.prompter, "Do you want to add co-authors?")
+	if err != nil {
+		return fmt.Errorf("asking about co-authors: %w", err)
+	}
+
+	for add {
+		added, err := w.pickPerson(commit.CoAuthorKey, "Select a co-author")
+		if err != nil {
+			return fmt.Errorf("selecting co-author: %w", err)
+		}
+		if !added {
+			return nil
+		}
+
+		add, err = ui.ConfirmSelect(w.prompter, "Do you want to add another co-author?")
+		if err != nil {
+			return fmt.Errorf("asking about more co-authors: %w", err)
+		}
+	}
+	return nil
+}
+
+// keepPeople asks whether to keep the people credited under the trailer keys by the
+// amended commit, and forgets them otherwise.
+func (w *wizard) keepPeople(what string, keys []string) error {
+	values := []string{}
+	for _, people := range commit.People(&w.config) {
+		if slices.Contains(keys, people.Key) {
+			for _, value := range *people.Values {
+				values = append(values, people.Key+": "+value)
+			}
+		}
+	}
+
+	keep, err := w.keep(what, values)
+	if err != nil || keep {
+		return err
+	}
+	for _, people := range commit.People(&w.config) {
+		if slices.Contains(keys, people.Key) {
+			*people.Values = nil
+		}
+	}
+	return nil
+}
+
+// peopleFromFlags credits the people given with the flags of the trailer keys, and
+// reports whether any of those flags was given.
+func (w *wizard) peopleFromFlags(keys []string) (bool, error) {
+	given := false
+	for _, role := range commit.Roles {
+		if !slices.Contains(keys, role.Key) || !w.opts.has(role.Flag) {
+			continue
+		}
+
+		given = true
+		// Flags are not shown before committing, so only exact names and emails are
+		// completed.
+		for _, value := range *w.opts.People[role.Key] {
+			id, err := identity.Resolve(value, w.known())
+			if err != nil {
+				return true, newUsageError("--%s: %v", role.Flag, err)
+			}
+			w.addPerson(role.Key, id)
+		}
+	}
+	return given, nil
+}
+
+// pickPerson asks for a person to credit under the trailer key, proposing the known
+// people not credited there yet. It reports false when the user picked nobody.
+func (w *wizard) pickPerson(key, label string) (bool, error) {
+	options := []ui.Suggestion{}
+	for _, candidate := range w.candidates() {
+		if !w.credited(key, candidate.id) {
+			options = append(options, ui.Suggestion{Value: candidate.id.String(), Detail: candidate.detail})
+		}
+	}
+
+	value, err := ui.SelectSuggestion(w.prompter, label, "Name <email> (e.g., 'Jane Doe <jane@example.com>')", options, w.validatePerson)
+	if err != nil || value == "" {
+		return false, err
+	}
+	id, err := identity.Complete(value, w.known())
+	if err != nil {
+		return false, err
+	}
+	w.addPerson(key, id)
+	return true, nil
+}
+
+// addPerson credits id under the trailer key, unless it is already credited there.
+func (w *wizard) addPerson(key string, id identity.Identity) {
+	if w.credited(key, id) {
+		return
+	}
+	for _, people := range commit.People(&w.config) {
+		if people.Key == key {
+			*people.Values = append(*people.Values, id.String())
+		}
+	}
+}
+
+// credited reports whether id is already credited under the trailer key.
+func (w *wizard) credited(key string, id identity.Identity) bool {
+	for _, people := range commit.People(&w.config) {
+		if people.Key != key {
+			continue
+		}
+		for _, value := range *people.Values {
+			if other, err := identity.Parse(value); err == nil && other.Same(id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validatePerson accepts "Name <email>", the bare names and emails of known people and
+// the start of a known name.
+func (w *wizard) validatePerson(input string) error {
+	_, err := identity.Complete(input, w.known())
+	return err
+}
+
+// known returns the identities of the known people.
+func (w *wizard) known() []identity.Identity {
+	ids := []identity.Identity{}
+	for _, candidate := range w.candidates() {
+		ids = append(ids, candidate.id)
+	}
+	return ids
+}
+
+// candidates returns the people proposed by the pickers: the recent co-authors, the
+// team roster, then the identities of .mailmap and of the authors in git log, without
+// duplicates and without the user. They are a convenience, so sources that cannot be
+// read are skipped.
+func (w *wizard) candidates() []person {
+	if w.people != nil {
+		return w.people
+	}
+
+	// The user is already the author of the commit.
+	self, _ := w.git.Config("user.email")
+
+	w.people = []person{}
+	add := func(text, detail string) {
+		id, err := identity.Parse(text)
+		if err != nil || strings.EqualFold(id.Email, self) {
+			return
+		}
+		for _, p := range w.people {
+			if p.id.Same(id) {
+				return
+			}
+		}
+		w.people = append(w.people, person{id: id, detail: detail})
+	}
+
+	if w.loadHistory() == nil {
+		now := time.Now()
+		for _, coAuthor := range w.history.CoAuthors {
+			add(coAuthor.Identity, "co-author "+history.Ago(coAuthor.LastUsed, now))
+		}
+	}
+
+	if w.settings.Team.Roster != "" {
+		if roster, err := identity.ReadRoster(w.settings.Team.Roster); err == nil {
+			for _, id := range roster {
+				add(id.String(), "team")
+			}
+		}
+	}
+
+	if dir, err := os.Getwd(); err == nil {
+		if root := cfg.FindGitRoot(dir); root != "" {
+			if mailmap, err := identity.ReadMailmap(filepath.Join(root, ".mailmap")); err == nil {
+				for _, id := range mailmap {
+					add(id.String(), ".mailmap")
+				}
+			}
+		}
+	}
+
+	if authors, err := w.git.Authors(); err == nil {
+		for _, author := range authors {
+			add(author, "git log")
+		}
+	}
+
+	return w.people
+}
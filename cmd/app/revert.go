@@ -0,0 +1,319 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	commit "github.com/GiulianoPoeta99/conventional_commits_cli/internal"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/git"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/history"
+	t "github.com/GiulianoPoeta99/conventional_commits_cli/internal/types"
+	ui "github.com/GiulianoPoeta99/conventional_commits_cli/internal/ui"
+)
+
+// revertDraftName is the file under the git directory holding the revert in progress.
+const revertDraftName = "commit-revert.json"
+
+// revertCandidates is the number of recent commits proposed by the revert picker.
+const revertCandidates = 200
+
+// revertDraft is a revert started by the wizard. It is saved under the git directory
+// until the revert is committed, so that a revert stopped by conflicts resumes when
+// the wizard runs again.
+type revertDraft struct {
+	// Commits are the hashes of the commits to revert, newest first.
+	Commits []string `json:"commits"`
+	// Applied counts the commits already reverted in the index, the one stopped by
+	// conflicts included.
+	Applied int `json:"applied"`
+}
+
+// prepareRevert resumes the revert in progress, or starts the one asked with --revert.
+func (w *wizard) prepareRevert() error {
+	if w.opts.has("revert") && w.opts.has("type") && w.opts.Type != commit.RevertType {
+		return newUsageError("--revert cannot be combined with --type %s", w.opts.Type)
+	}
+
+	resumed, err := w.resumeRevert()
+	if err != nil || resumed {
+		return err
+	}
+	if !w.opts.has("revert") {
+		return nil
+	}
+
+	commits := []git.Commit{}
+	for _, revision := range w.opts.Reverts {
+		c, err := w.git.CommitAt(revision)
+		if err != nil {
+			return newUsageError("--revert: %v", err)
+		}
+		commits = append(commits, c)
+	}
+	return w.startRevert(commits)
+}
+
+// askRevert offers, once the revert type is selected, to pick the commits to revert
+// instead of describing the revert by hand.
+func (w *wizard) askRevert() error {
+	if !w.reverts || w.revert != nil || w.amended != nil || !w.interactive || w.config.Type.Code != commit.RevertType {
+		return nil
+	}
+
+	pick, err := ui.ConfirmSelectDefault(w.prompter, "Do you want to pick the commits to revert from the history?", true)
+	if err != nil {
+		return fmt.Errorf("asking about the commits to revert: %w", err)
+	}
+	if !pick {
+		return nil
+	}
+
+	commits, err := w.selectReverted()
+	if err != nil {
+		return err
+	}
+	return w.startRevert(commits)
+}
+
+// selectReverted asks for the commits to revert among the recent ones, searchable by
+// header, hash or author.
+func (w *wizard) selectReverted() ([]git.Commit, error) {
+	recent, err := w.git.Recent(revertCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	chosen := []git.Commit{}
+	for {
+		candidates := []git.Commit{}
+		items := []string{}
+		for _, c := range recent {
+			if slices.Contains(chosen, c) {
+				continue
+			}
+			ago := c.Date
+			if date, err := time.Parse(time.RFC3339, c.Date); err == nil {
+				ago = history.Ago(date, now)
+			}
+			candidates = append(candidates, c)
+			items = append(items, fmt.Sprintf("%s %s (%s, %s)", c.ShortHash(), c.Header(), c.Author, ago))
+		}
+		if len(candidates) == 0 {
+			break
+		}
+
+		index, err := w.prompter.Select("Select the commit to revert (type / to search by header, hash or author)", items, ui.SelectOptions{
+			Size: min(len(items), 10),
+			Searcher: func(input string, index int) bool {
+				input = strings.ToLower(strings.TrimSpace(input))
+				return strings.Contains(strings.ToLower(items[index]), input) || strings.HasPrefix(candidates[index].Hash, input)
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("selecting the commit to revert: %w", err)
+		}
+		chosen = append(chosen, candidates[index])
+
+		more, err := ui.ConfirmSelect(w.prompter, "Do you want to revert another commit?")
+		if err != nil {
+			return nil, fmt.Errorf("asking about more commits to revert: %w", err)
+		}
+		if !more {
+			break
+		}
+	}
+
+	return chosen, nil
+}
+
+// startRevert reverts the commits in the index and fills the message of the revert.
+// The newest commits are reverted first, which avoids needless conflicts.
+func (w *wizard) startRevert(commits []git.Commit) error {
+	if _, err := w.revertType(); err != nil {
+		return err
+	}
+
+	sorted := []git.Commit{}
+	for _, c := range commits {
+		if slices.Contains(sorted, c) {
+			continue
+		}
+		i := len(sorted)
+		for j, other := range sorted {
+			if older, err := w.git.IsAncestor(other.Hash, c.Hash); err == nil && older {
+				i = j
+				break
+			}
+		}
+		sorted = slices.Insert(sorted, i, c)
+	}
+	commits = sorted
+
+	w.revert = &revertDraft{}
+	for _, c := range commits {
+		w.revert.Commits = append(w.revert.Commits, c.Hash)
+	}
+	if err := w.applyReverts(); err != nil {
+		return err
+	}
+	return w.fillRevert(commits)
+}
+
+// resumeRevert continues the revert saved by a previous run, once its conflicts are
+// resolved, and reports whether there was one. A draft left behind by a revert
+// aborted with `git revert --abort` or committed by hand is discarded.
+func (w *wizard) resumeRevert() (bool, error) {
+	path, err := w.git.GitPath(revertDraftName)
+	if err != nil {
+		return false, err
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	draft := &revertDraft{}
+	if err := json.Unmarshal(content, draft); err != nil {
+		return false, fmt.Errorf("%s: %w", path, err)
+	}
+
+	reverting, err := w.git.Reverting()
+	if err != nil {
+		return false, err
+	}
+	if !reverting || len(draft.Commits) == 0 {
+		return false, os.Remove(path)
+	}
+
+	unmerged, err := w.git.UnmergedFiles()
+	if err != nil {
+		return false, err
+	}
+	if len(unmerged) > 0 {
+		return true, w.pauseRevert(draft.Commits[max(draft.Applied-1, 0)], unmerged)
+	}
+
+	commits := []git.Commit{}
+	hashes := []string{}
+	for _, hash := range draft.Commits {
+		c, err := w.git.CommitAt(hash)
+		if err != nil {
+			return true, err
+		}
+		commits = append(commits, c)
+		hashes = append(hashes, c.ShortHash())
+	}
+	w.printf("Resuming the revert of %s\n", strings.Join(hashes, ", "))
+
+	w.revert = draft
+	if err := w.applyReverts(); err != nil {
+		return true, err
+	}
+	return true, w.fillRevert(commits)
+}
+
+// applyReverts reverts the commits of the draft not applied yet, saving the draft after
+// each one. It stops at the first commit whose revert conflicts.
+func (w *wizard) applyReverts() error {
+	for w.revert.Applied < len(w.revert.Commits) {
+		hash := w.revert.Commits[w.revert.Applied]
+		conflicts, err := w.git.Revert(hash)
+		if err != nil {
+			return fmt.Errorf("reverting %s: %w", git.Commit{Hash: hash}.ShortHash(), err)
+		}
+
+		w.revert.Applied++
+		if err := w.saveRevert(); err != nil {
+			return err
+		}
+		if len(conflicts) > 0 {
+			return w.pauseRevert(hash, conflicts)
+		}
+	}
+	return nil
+}
+
+// pauseRevert explains how to resolve the conflicts of the revert of hash and stops the
+// wizard without committing.
+func (w *wizard) pauseRevert(hash string, conflicts []string) error {
+	fmt.Fprintf(os.Stderr, "Reverting %s stopped on conflicts in:\n", git.Commit{Hash: hash}.ShortHash())
+	for _, path := range conflicts {
+		fmt.Fprintf(os.Stderr, "  %s\n", path)
+	}
+	fmt.Fprintln(os.Stderr, "Resolve them and stage the files with git add, then run commit again to finish the revert,")
+	fmt.Fprintln(os.Stderr, "or run git revert --abort to give it up.")
+	return &exitError{code: 1}
+}
+
+// fillRevert sets the message of the revert of the commits, given newest first.
+func (w *wizard) fillRevert(commits []git.Commit) error {
+	revertType, err := w.revertType()
+	if err != nil {
+		return err
+	}
+	w.config = commit.RevertConfig(revertType, commits, w.settings.Types, w.settings.Emojis)
+	return nil
+}
+
+// revertType returns the configured commit type of reverts.
+func (w *wizard) revertType() (t.CommitType, error) {
+	revertType, ok := w.settings.FindType(commit.RevertType)
+	if !ok {
+		return t.CommitType{}, fmt.Errorf("the %s commit type is not configured", commit.RevertType)
+	}
+	return revertType, nil
+}
+
+// revertPending reports whether a revert is asked for with --revert, or was left by a
+// previous run and is about to be resumed.
+func (w *wizard) revertPending() (bool, error) {
+	if len(w.opts.Reverts) > 0 {
+		return true, nil
+	}
+	path, err := w.git.GitPath(revertDraftName)
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// saveRevert writes the draft of the revert under the git directory.
+func (w *wizard) saveRevert() error {
+	path, err := w.git.GitPath(revertDraftName)
+	if err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(w.revert, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0o644)
+}
+
+// finishRevert forgets the draft of the revert once it is committed.
+func (w *wizard) finishRevert() error {
+	if w.revert == nil {
+		return nil
+	}
+	path, err := w.git.GitPath(revertDraftName)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
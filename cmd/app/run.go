@@ -1,191 +1,835 @@
-// Package main is the entry point of the Conventional Commits Assistant application.
 package app
 
 import (
 	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"slices"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	commit "github.com/GiulianoPoeta99/conventional_commits_cli/internal"
+	cfg "github.com/GiulianoPoeta99/conventional_commits_cli/internal/config"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/editor"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/git"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/history"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/infer"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/issue"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/lint"
 	t "github.com/GiulianoPoeta99/conventional_commits_cli/internal/types"
 	ui "github.com/GiulianoPoeta99/conventional_commits_cli/internal/ui"
 )
 
-// main is the entry point for the application.
-// It collects user inputs to build a commit message following Conventional Commits standards,
-// then formats and executes the commit.
+// Run is the entry point for the application.
+// It collects user inputs, from flags or prompts, to build a commit message following
+// Conventional Commits standards, then formats and executes the commit.
 func Run() {
+	g := git.NewExec("")
+
+	// Dispatch to a subcommand when its name is the first argument.
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			err := command(g, os.Args[2:])
+			if errors.Is(err, flag.ErrHelp) {
+				return
+			}
+			if err != nil {
+				var exit *exitError
+				if !errors.As(err, &exit) || exit.err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				}
+				os.Exit(exitCode(err))
+			}
+			return
+		}
+	}
+
+	opts, err := parseOptions(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitCode(err))
+	}
+
+	w := &wizard{git: g, prompter: ui.NewPromptui(), opts: opts, interactive: ui.IsInteractive()}
+
+	// Replay a script of answers instead of prompting, e.g. for end-to-end tests.
+	var script *ui.Scripted
+	if opts.has("answers") {
+		script, err = ui.LoadScript(opts.Answers)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: loading answers: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+		script.Transcript = os.Stdout
+		w.prompter = script
+		w.interactive = true
+	}
+
 	// Print welcome message for the assistant.
-	fmt.Println("🚀 Conventional Commits Assistant")
+	if w.interactive {
+		fmt.Println("🚀 Conventional Commits Assistant")
+	}
+
+	err = w.run()
+	if err == nil && script != nil && script.Remaining() > 0 {
+		err = fmt.Errorf("answers script has %d unused answers", script.Remaining())
+	}
+	if err != nil {
+		var exit *exitError
+		if !errors.As(err, &exit) || exit.err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(exitCode(err))
+	}
+
+	// Notify the user that the commit was created successfully.
+	if opts.Amend {
+		fmt.Println("✅ Commit successfully amended")
+	} else {
+		fmt.Println("✅ Commit successfully created")
+	}
+}
+
+// wizard collects the commit configuration, skipping every prompt whose value
+// was already supplied on the command line.
+type wizard struct {
+	git         git.Git
+	prompter    ui.Prompter
+	tty         *os.File  // terminal of the messages and editor; nil means the process streams
+	out         io.Writer // writer of the messages when set, in place of tty and stdout
+	settings    cfg.Config
+	opts        options
+	interactive bool
+	config      t.CommitConfig
+	history     *history.History
+	historyPath string
+	people      []person        // candidates of the people pickers, loaded on first use
+	amended     *t.CommitConfig // message of HEAD read by --amend
+	reverts     bool            // whether the wizard may revert commits, as it makes the commit
+	revert      *revertDraft    // revert in progress, see revert.go
+	draft       bool            // whether the message was resumed from a draft, see confirm.go
+}
 
-	// Initialize an empty commit configuration.
-	config := t.CommitConfig{}
+// run collects every field and commits the result.
+func (w *wizard) run() error {
+	// Unlike the prepare-commit-msg hook, the wizard commits the reverts it applies.
+	w.reverts = true
+
+	// Staging comes first, as the type and scope are inferred from the staged files.
+	// The prepare-commit-msg hook cannot stage, as git holds the index lock.
+	if err := w.askStage(); err != nil {
+		return err
+	}
+
+	if err := w.collect(); err != nil {
+		return err
+	}
 
+	if err := w.restage(); err != nil {
+		return err
+	}
+
+	// Commit straight away when confirmation is impossible or was waived.
+	if w.opts.Yes || !w.interactive {
+		// Without the preview, the header length is only warned about.
+		message := w.message()
+		if warning := commit.HeaderWarning(message, w.settings.Message.HeaderMaxLength); warning != "" {
+			fmt.Fprintf(os.Stderr, "⚠️  %s\n", warning)
+		}
+		if err := commit.Commit(w.git, message, w.opts.commitOptions()); err != nil {
+			return fmt.Errorf("committing: %w", err)
+		}
+	} else {
+		// Review the message, which may still be edited, before committing it.
+		if err := w.confirm(); err != nil {
+			return err
+		}
+	}
+
+	if err := w.finishRevert(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: removing the revert draft: %v\n", err)
+	}
+	if err := w.finishDraft(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: removing the message draft: %v\n", err)
+	}
+
+	// Remember the scope and co-authors for the next commits; failing to do so does
+	// not undo the commit.
+	if err := w.remember(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: saving the scope history: %v\n", err)
+	}
+	return nil
+}
+
+// message formats the collected configuration, wrapped at the configured width.
+func (w *wizard) message() string {
+	// References are grouped by relation in the configured order.
+	config := w.config
+	config.ReferenceIssues = issue.SortReferences(config.ReferenceIssues, w.settings.Issues.Relations)
+	return commit.FormatCommitMessage(commit.WrapCommitConfig(config, w.settings.Message.WrapWidth))
+}
+
+// collect loads the settings and fills the commit configuration from flags and prompts.
+func (w *wizard) collect() error {
 	var err error
 
-	// Prompt user to select the commit type.
-	config.Type, err = ui.SelectCommitType()
+	// Load the user and repository settings merged over the built-in defaults.
+	w.settings, err = cfg.Load()
 	if err != nil {
-		fmt.Printf("Error selecting commit type: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("loading configuration: %w", err)
 	}
 
-	// Ask the user to provide an optional scope for the commit.
-	config.Scope, err = ui.OptionalInput("Add a scope for this change. (optional, press Enter to omit)")
+	// Amending starts from the message of HEAD, reverting from the reverted commits, and
+	// other commits may start from a draft.
+	if w.opts.Amend {
+		if w.opts.has("revert") {
+			return newUsageError("--revert cannot be combined with --amend")
+		}
+		if err := w.loadAmended(); err != nil {
+			return err
+		}
+	} else if w.reverts {
+		if err := w.prepareRevert(); err != nil {
+			return err
+		}
+		if w.revert == nil {
+			if err := w.resumeDraft(); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Without a terminal nothing can be asked, so required fields must come from flags
+	// or the amended commit.
+	if !w.interactive {
+		missing := []string{}
+		if !w.opts.has("type") && w.config.Type.Code == "" {
+			missing = append(missing, "--type")
+		}
+		if !w.opts.has("description") && w.config.Description == "" {
+			missing = append(missing, "--description")
+		}
+		if len(missing) > 0 {
+			return newUsageError(
+				"missing required flags: %s (stdin is not a terminal, prompts are disabled)",
+				strings.Join(missing, ", "),
+			)
+		}
+	}
+
+	return w.ask()
+}
+
+// ask fills every field of the commit configuration from flags and prompts.
+func (w *wizard) ask() error {
+	steps := []func() error{
+		w.askType,
+		w.askRevert,
+		w.askScope,
+		w.askEmoji,
+		w.askDescription,
+		w.askBody,
+		w.askBreaking,
+		w.askReviewers,
+		w.askCoAuthors,
+		w.askIssues,
+		w.keepTrailers,
+		w.signoff,
+		w.askTrailers,
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// askType selects the commit type from the --type flag or a prompt.
+func (w *wizard) askType() error {
+	if w.revert != nil {
+		return nil
+	}
+	if w.opts.has("type") {
+		commitType, ok := w.settings.FindType(w.opts.Type)
+		if !ok {
+			return newUsageError("unknown commit type %q", w.opts.Type)
+		}
+		w.config.Type = commitType
+		return nil
+	}
+	if !w.interactive {
+		return nil
+	}
+
+	// Prompt user to select the commit type, pre-selecting the current one when amending,
+	// resuming a draft or editing the type, or else the one inferred from the staged changes.
+	suggestion := w.typeSuggestion()
+	if w.config.Type.Code != "" {
+		suggestion = ui.Suggestion{Value: w.config.Type.Code, Detail: "current type"}
+	}
+	var err error
+	w.config.Type, err = ui.SelectCommitType(w.prompter, w.settings.Types, suggestion)
 	if err != nil {
-		fmt.Printf("Error entering scope: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("selecting commit type: %w", err)
 	}
+	return nil
+}
 
-	// Confirm if the user wants to include an emoji with the commit.
-	useEmoji, err := ui.ConfirmSelect("Do you want to include an emoji?")
+// typeSuggestion infers the commit type from the staged changes. Like the scope
+// suggestions it is a convenience, so failures simply yield no suggestion.
+func (w *wizard) typeSuggestion() ui.Suggestion {
+	files, err := w.git.StagedFiles()
+	if err != nil {
+		return ui.Suggestion{}
+	}
+	patch, err := w.git.StagedDiff()
 	if err != nil {
-		fmt.Printf("Error selecting emoji option: %v\n", err)
-		os.Exit(1)
+		return ui.Suggestion{}
 	}
 
-	// If emoji is desired, prompt for emoji selection with suggestions based on commit type.
-	if useEmoji {
-		config.Emoji, err = ui.SelectEmojiWithSuggestions(config.Type)
-		if err != nil {
-			fmt.Printf("Error selecting emoji: %v\n", err)
-			os.Exit(1)
+	suggestion, ok, err := infer.Type(files, patch, w.settings.TypeRules)
+	if err != nil || !ok {
+		return ui.Suggestion{}
+	}
+	return ui.Suggestion{Value: suggestion.Type, Detail: "suggested: " + suggestion.Reason}
+}
+
+// askScope reads the optional scope from the --scope flag or a prompt.
+// The prompt proposes the scopes inferred from the staged files, then the scopes
+// remembered for the repository, most frecently used first. When amending, the
+// current scope comes first.
+func (w *wizard) askScope() error {
+	if w.opts.has("scope") {
+		w.config.Scope = w.opts.Scope
+		return nil
+	}
+	if !w.interactive {
+		return nil
+	}
+
+	suggestions := w.scopeSuggestions()
+	options := []ui.Suggestion{}
+	seen := map[string]bool{}
+	if w.config.Scope != "" {
+		options = append(options, ui.Suggestion{Value: w.config.Scope, Detail: "current scope"})
+		seen[w.config.Scope] = true
+	}
+	for _, suggestion := range suggestions {
+		if seen[suggestion.Scope] {
+			continue
 		}
+		options = append(options, ui.Suggestion{
+			Value:  suggestion.Scope,
+			Detail: fmt.Sprintf("%d file(s), %s", len(suggestion.Files), suggestion.Reason),
+		})
+		seen[suggestion.Scope] = true
 	}
 
-	// Request user input for the commit description with validation.
-	config.Description, err = ui.InputWithValidation(
-		"Commit description",
-		"",
-		func(input string) error {
-			if len(input) < 3 {
-				return errors.New("description must have at least 3 characters")
+	// Changes spanning several scopes are usually better split into several commits.
+	if len(suggestions) > 1 {
+		scopes := []string{}
+		for _, suggestion := range suggestions {
+			scopes = append(scopes, suggestion.Scope)
+		}
+		w.printf("⚠️  The staged files span %d scopes (%s); consider splitting this commit.\n", len(scopes), strings.Join(scopes, ", "))
+		options = append(options, ui.Suggestion{Value: strings.Join(scopes, ","), Detail: "every staged scope"})
+	}
+
+	if w.loadHistory() == nil {
+		now := time.Now()
+		for _, entry := range w.history.Ranked(now) {
+			if seen[entry.Scope] {
+				continue
 			}
-			return nil
-		},
-	)
+			options = append(options, ui.Suggestion{
+				Value:  entry.Scope,
+				Detail: fmt.Sprintf("used %d time(s), last %s", entry.Count, history.Ago(entry.LastUsed, now)),
+			})
+		}
+	}
+
+	var err error
+	if len(options) == 0 {
+		// Ask the user to provide an optional scope for the commit.
+		w.config.Scope, err = ui.OptionalInput(w.prompter, "Add a scope for this change. (optional, press Enter to omit)")
+		if err != nil {
+			return fmt.Errorf("entering scope: %w", err)
+		}
+		return nil
+	}
+
+	w.config.Scope, err = ui.SelectSuggestion(w.prompter, "Select a scope for this change", "Scope", options, nil)
 	if err != nil {
-		fmt.Printf("Error entering description: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("selecting scope: %w", err)
+	}
+	return nil
+}
+
+// loadHistory loads the scope history of the repository, bringing it up to date
+// with the commits made since it was last saved.
+func (w *wizard) loadHistory() error {
+	if w.history != nil {
+		return nil
 	}
 
-	// Ask for an optional commit body.
-	config.Body, err = ui.OptionalInput("Commit body (optional, press Enter to omit)")
+	path, err := scopeHistoryPath()
 	if err != nil {
-		fmt.Printf("Error entering body: %v\n", err)
-		os.Exit(1)
+		return err
+	}
+	h, err := history.Load(path)
+	if err != nil {
+		return err
 	}
 
-	// Confirm if the change is breaking.
-	config.Breaking, err = ui.ConfirmSelect("Is this a breaking change?")
+	// The history is only a convenience: an empty repository simply has nothing to
+	// import. Saving what was imported spares the next runs reading the same commits;
+	// when it fails, remember reports it after committing.
+	if commits, _, err := importScopes(w.git, w.settings, h); err == nil && commits > 0 {
+		_ = h.Save(path)
+	}
+
+	w.history, w.historyPath = h, path
+	return nil
+}
+
+// remember records the scope and co-authors of the commit just created in the history.
+func (w *wizard) remember() error {
+	if w.config.Scope == "" && len(w.config.CoAuthors) == 0 {
+		return nil
+	}
+	if err := w.loadHistory(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, coAuthor := range w.config.CoAuthors {
+		w.history.UseCoAuthor(coAuthor, now)
+	}
+	// An amended commit already counted its scope, unless it changed.
+	if w.config.Scope != "" && (w.amended == nil || w.amended.Scope != w.config.Scope) {
+		// Mark the new commit as imported so the next import does not count it twice.
+		imported := w.history.Imported
+		w.history.Use(w.config.Scope, now)
+		if commits, err := w.git.Log(imported, ""); err == nil && len(commits) == 1 {
+			w.history.Imported = commits[0].Hash
+		}
+	}
+
+	return w.history.Save(w.historyPath)
+}
+
+// scopeSuggestions infers scopes from the staged files. Suggestions are a convenience,
+// so failures to inspect the repository simply yield none.
+func (w *wizard) scopeSuggestions() []infer.ScopeSuggestion {
+	files, err := w.git.StagedFiles()
 	if err != nil {
-		fmt.Printf("Error selecting breaking change: %v\n", err)
-		os.Exit(1)
+		return nil
 	}
 
-	// If the change is breaking, request an optional explanation.
-	if config.Breaking {
-		config.BreakingReason, err = ui.OptionalInput("Describe why this is a breaking change (optional, press Enter to use the default message)")
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	root := cfg.FindGitRoot(dir)
+	if root == "" {
+		root = dir
+	}
+
+	suggestions, err := infer.Scopes(root, files, w.settings.Scopes.Rules)
+	if err != nil {
+		return nil
+	}
+	return suggestions
+}
+
+// printf writes a message to the output of the wizard.
+func (w *wizard) printf(format string, args ...any) {
+	fmt.Fprintf(w.output(), format, args...)
+}
+
+// output returns the writer of the messages: out, the terminal or stdout.
+func (w *wizard) output() io.Writer {
+	switch {
+	case w.out != nil:
+		return w.out
+	case w.tty != nil:
+		return w.tty
+	default:
+		return os.Stdout
+	}
+}
+
+// askEmoji selects the optional emoji from the --emoji flag or a prompt.
+func (w *wizard) askEmoji() error {
+	if w.opts.has("emoji") {
+		emoji, ok := w.findEmoji(w.opts.Emoji)
+		if !ok {
+			return newUsageError("unknown emoji %q", w.opts.Emoji)
+		}
+		w.config.Emoji = emoji
+		return nil
+	}
+	if !w.interactive {
+		return nil
+	}
+
+	// Confirm if the user wants to include an emoji with the commit.
+	useEmoji, err := ui.ConfirmSelectDefault(w.prompter, "Do you want to include an emoji?", w.config.Emoji.Code != "")
+	if err != nil {
+		return fmt.Errorf("selecting emoji option: %w", err)
+	}
+	if !useEmoji {
+		w.config.Emoji = t.Emoji{}
+		return nil
+	}
+
+	// Prompt for emoji selection with suggestions based on commit type.
+	w.config.Emoji, err = ui.SelectEmojiWithSuggestions(w.prompter, w.config.Type, w.config.Emoji, w.settings.Emojis, w.settings.TypeEmojis)
+	if err != nil {
+		return fmt.Errorf("selecting emoji: %w", err)
+	}
+	return nil
+}
+
+// findEmoji looks an emoji up by code (with or without colons) or by symbol.
+func (w *wizard) findEmoji(value string) (t.Emoji, bool) {
+	if emoji, ok := w.settings.FindEmoji(strings.Trim(value, ":")); ok {
+		return emoji, true
+	}
+	for _, emoji := range w.settings.Emojis {
+		if emoji.Symbol == value {
+			return emoji, true
+		}
+	}
+	return t.Emoji{}, false
+}
+
+// askDescription reads the commit description from the --description flag or a prompt.
+func (w *wizard) askDescription() error {
+	if w.opts.has("description") {
+		if err := validateDescription(w.opts.Description); err != nil {
+			return newUsageError("--description: %v", err)
+		}
+		w.config.Description = w.opts.Description
+		return nil
+	}
+	if !w.interactive {
+		return nil
+	}
+
+	// Request user input for the commit description with validation.
+	var err error
+	w.config.Description, err = ui.InputWithValidation(w.prompter, "Commit description", w.config.Description, validateDescription)
+	if err != nil {
+		return fmt.Errorf("entering description: %w", err)
+	}
+	return nil
+}
+
+// multilineBodyLabel is the label of each line of a body entered in the terminal.
+const multilineBodyLabel = "Commit body line (optional, empty line or Ctrl+D to finish)"
+
+// askBody reads the optional commit body from the --body flag or a prompt.
+// The prompt is a single line, terminal lines or the editor, depending on the
+// --editor flag and the message.bodyInput setting.
+func (w *wizard) askBody() error {
+	if w.opts.has("body") {
+		w.config.Body = w.opts.Body
+		return nil
+	}
+	if !w.interactive {
+		return nil
+	}
+
+	mode := w.settings.Message.BodyInput
+	if w.opts.Editor {
+		mode = cfg.BodyInputEditor
+	}
+
+	// The editor opens on the current body; the terminal prompts may only keep it.
+	if w.config.Body != "" && mode != cfg.BodyInputEditor {
+		w.printf("Current body:\n\n%s\n\n", w.config.Body)
+		keep, err := ui.ConfirmSelectDefault(w.prompter, "Keep the current body?", true)
 		if err != nil {
-			fmt.Printf("Error entering breaking change reason: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("asking about the current body: %w", err)
+		}
+		if keep {
+			return nil
 		}
 	}
 
-	// Confirm whether the user wants to add reviewers.
-	addReviewers, err := ui.ConfirmSelect("Do you want to add reviewers?")
+	var err error
+	switch mode {
+	case cfg.BodyInputEditor:
+		w.config.Body, err = w.editBody()
+	case cfg.BodyInputMultiline:
+		w.config.Body, err = ui.MultilineInput(w.prompter, multilineBodyLabel)
+	default:
+		// Ask for an optional commit body.
+		w.config.Body, err = ui.OptionalInput(w.prompter, "Commit body (optional, press Enter to omit)")
+	}
 	if err != nil {
-		fmt.Printf("Error asking about reviewers: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("entering body: %w", err)
 	}
+	return nil
+}
 
-	// Collect the list of reviewers if confirmed.
-	if addReviewers {
-		for {
-			reviewer, err := ui.InputWithValidation(
-				"Enter reviewer (e.g., 'John Smith')",
-				"",
-				func(input string) error {
-					if len(input) < 1 {
-						return errors.New("reviewer name cannot be empty")
-					}
-					return nil
-				},
-			)
-			if err != nil {
-				fmt.Printf("Error entering reviewer: %v\n", err)
-				os.Exit(1)
-			}
+// editBody opens the body in the user's editor, falling back to multi-line
+// terminal input when no editor is configured.
+func (w *wizard) editBody() (string, error) {
+	command, err := editor.Find(w.git)
+	if err != nil {
+		return "", err
+	}
+	if command == "" {
+		return ui.MultilineInput(w.prompter, multilineBodyLabel)
+	}
+
+	// Show the header being written and explain the expected content below the body.
+	header := commit.FormatCommitMessage(t.CommitConfig{
+		Type:        w.config.Type,
+		Scope:       w.config.Scope,
+		Emoji:       w.config.Emoji,
+		Description: w.config.Description,
+	})
+	template := w.config.Body + "\n" +
+		"# Write the body of the commit for:\n" +
+		"#\n" +
+		"#   " + header + "\n" +
+		"#\n" +
+		"# Explain what changed and why; paragraphs and bullet lists are welcome.\n" +
+		"# Lines starting with '#' are ignored, and an empty body leaves it out.\n"
+
+	e := &editor.Editor{Command: command}
+	if w.tty != nil {
+		e.Stdin, e.Stdout, e.Stderr = w.tty, w.tty, w.tty
+	}
+	content, err := e.Edit(template)
+	if err != nil {
+		return "", err
+	}
+	return editor.Cleanup(content), nil
+}
+
+// askBreaking reads the breaking change marker and reason from flags or prompts.
+func (w *wizard) askBreaking() error {
+	if w.opts.has("breaking") || w.opts.has("breaking-reason") {
+		w.config.Breaking = w.opts.Breaking || w.opts.has("breaking-reason")
+		if w.opts.has("breaking-reason") || !w.config.Breaking {
+			w.config.BreakingReason = w.opts.BreakingReason
+		}
+		return nil
+	}
+	if !w.interactive {
+		return nil
+	}
+
+	// Confirm if the change is breaking.
+	var err error
+	w.config.Breaking, err = ui.ConfirmSelectDefault(w.prompter, "Is this a breaking change?", w.config.Breaking)
+	if err != nil {
+		return fmt.Errorf("selecting breaking change: %w", err)
+	}
+	if !w.config.Breaking {
+		w.config.BreakingReason = ""
+		return nil
+	}
 
-			config.Reviewers = append(config.Reviewers, reviewer)
+	// Request an optional explanation of the breaking change.
+	w.config.BreakingReason, err = w.prompter.Input("Describe why this is a breaking change (optional, press Enter to use the default message)", w.config.BreakingReason, nil)
+	if err != nil {
+		return fmt.Errorf("entering breaking change reason: %w", err)
+	}
+	return nil
+}
 
-			addMore, err := ui.ConfirmSelect("Do you want to add another reviewer?")
+// askIssues collects issue references from the --ref flags or a prompt loop, asking
+// how the commit relates to each issue. The references found in the branch name are
+// pre-filled.
+func (w *wizard) askIssues() error {
+	if w.opts.has("ref") || !w.interactive {
+		for _, value := range w.opts.Refs {
+			ref, err := w.parseReference(value)
 			if err != nil {
-				fmt.Printf("Error asking about more reviewers: %v\n", err)
-				os.Exit(1)
+				return newUsageError("--ref: %v", err)
 			}
+			if !w.referenced(ref.Issue) {
+				w.config.ReferenceIssues = append(w.config.ReferenceIssues, ref)
+			}
+		}
+		// Without a prompt to confirm them, the references of the branch are only added
+		// when the configuration asks for it.
+		if !w.opts.has("ref") && w.settings.Issues.FromBranchUnattended {
+			for _, value := range w.branchIssues() {
+				w.config.ReferenceIssues = append(w.config.ReferenceIssues, t.IssueReference{Relation: w.defaultRelation(), Issue: value})
+			}
+		}
+		return nil
+	}
 
-			// Stop asking if no more reviewers are to be added.
-			if !addMore {
-				break
+	// When amending, the references of the commit may be kept.
+	values := []string{}
+	for _, ref := range w.config.ReferenceIssues {
+		relation := ref.Relation
+		if relation == "" {
+			relation = issue.DefaultRelation
+		}
+		values = append(values, relation+": "+ref.Issue)
+	}
+	keep, err := w.keep("issue references", values)
+	if err != nil {
+		return err
+	}
+	if !keep {
+		w.config.ReferenceIssues = nil
+	}
+
+	// Offer the references found in the branch name first.
+	if refs := w.branchIssues(); len(refs) > 0 {
+		useRefs, err := ui.ConfirmSelectDefault(w.prompter, fmt.Sprintf("Reference %s (from the branch name)?", strings.Join(refs, ", ")), true)
+		if err != nil {
+			return fmt.Errorf("asking about branch issue references: %w", err)
+		}
+		if useRefs {
+			for _, value := range refs {
+				if err := w.addReference(value); err != nil {
+					return err
+				}
 			}
 		}
 	}
 
 	// Confirm whether the user wants to reference issues.
-	refIssues, err := ui.ConfirmSelect("Do you want to reference issues?")
+	label := "Do you want to reference issues?"
+	if len(w.config.ReferenceIssues) > 0 {
+		label = "Do you want to reference other issues?"
+	}
+	refIssues, err := ui.ConfirmSelect(w.prompter, label)
 	if err != nil {
-		fmt.Printf("Error asking about issue references: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("asking about issue references: %w", err)
 	}
 
 	// Collect issue references if confirmed.
-	if refIssues {
-		for {
-			issue, err := ui.InputWithValidation(
-				"Enter issue reference (e.g., '#123')",
-				"#",
-				func(input string) error {
-					if !strings.HasPrefix(input, "#") {
-						return errors.New("issue reference must start with #")
-					}
-					if len(input) < 2 {
-						return errors.New("issue reference cannot be empty")
-					}
-					return nil
-				},
-			)
-			if err != nil {
-				fmt.Printf("Error entering issue reference: %v\n", err)
-				os.Exit(1)
-			}
+	for refIssues {
+		value, err := ui.InputWithValidation(w.prompter, "Enter issue reference (e.g., '#123', 'PROJ-123')", "", w.validateIssue)
+		if err != nil {
+			return fmt.Errorf("entering issue reference: %w", err)
+		}
+		if err := w.addReference(value); err != nil {
+			return err
+		}
+
+		// Stop asking if no more issue references are required.
+		refIssues, err = ui.ConfirmSelect(w.prompter, "Do you want to reference another issue?")
+		if err != nil {
+			return fmt.Errorf("asking about more issues: %w", err)
+		}
+	}
+	return nil
+}
 
-			config.ReferenceIssues = append(config.ReferenceIssues, issue)
+// addReference asks how the commit relates to an issue and records the reference.
+func (w *wizard) addReference(value string) error {
+	relations := w.settings.Issues.Relations
+	relation := relations[0]
 
-			addMore, err := ui.ConfirmSelect("Do you want to reference another issue?")
-			if err != nil {
-				fmt.Printf("Error asking about more issues: %v\n", err)
-				os.Exit(1)
+	if len(relations) > 1 {
+		items := []string{}
+		cursor := 0
+		for i, r := range relations {
+			items = append(items, fmt.Sprintf("%s: the commit %s", r, issue.RelationDescriptions[r]))
+			if r == w.defaultRelation() {
+				cursor = i
 			}
+		}
 
-			// Stop asking if no more issue references are required.
-			if !addMore {
-				break
-			}
+		index, err := w.prompter.Select(fmt.Sprintf("How does this commit relate to %s?", value), items, ui.SelectOptions{Cursor: cursor})
+		if err != nil {
+			return fmt.Errorf("selecting issue relation: %w", err)
 		}
+		relation = relations[index]
 	}
 
-	// Format the final commit message using the provided configuration.
-	commitMessage := commit.FormatCommitMessage(config)
+	w.config.ReferenceIssues = append(w.config.ReferenceIssues, t.IssueReference{Relation: relation, Issue: value})
+	return nil
+}
 
-	// Confirm the commit and proceed with execution.
-	err = commit.ConfirmAndCommit(commitMessage)
-	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		os.Exit(1)
+// parseReference reads a --ref value: an issue, optionally preceded by its relation
+// as in "closes=#123".
+func (w *wizard) parseReference(value string) (t.IssueReference, error) {
+	ref := t.IssueReference{Relation: w.defaultRelation(), Issue: value}
+	if name, rest, ok := strings.Cut(value, "="); ok {
+		if relation, known := issue.Relation(name); known {
+			ref = t.IssueReference{Relation: relation, Issue: rest}
+		}
 	}
 
-	// Notify the user that the commit was created successfully.
-	fmt.Println("✅ Commit successfully created")
+	if !slices.Contains(w.settings.Issues.Relations, ref.Relation) {
+		return ref, fmt.Errorf("relation %q is not enabled (expected %s)", ref.Relation, strings.Join(w.settings.Issues.Relations, ", "))
+	}
+	return ref, w.validateIssue(ref.Issue)
+}
+
+// defaultRelation returns the relation of references given without one: Refs when it
+// is enabled, otherwise the first configured relation.
+func (w *wizard) defaultRelation() string {
+	if slices.Contains(w.settings.Issues.Relations, issue.DefaultRelation) {
+		return issue.DefaultRelation
+	}
+	return w.settings.Issues.Relations[0]
+}
+
+// branchIssues returns the issue references found in the name of the current branch.
+func (w *wizard) branchIssues() []string {
+	if !w.settings.Issues.FromBranch {
+		return nil
+	}
+
+	branch, err := w.git.CurrentBranch()
+	if err != nil || branch == "" {
+		return nil
+	}
+	// An amended commit may already reference them.
+	refs := []string{}
+	for _, ref := range issue.FromBranch(branch, w.settings.Issues.BranchPatterns, w.settings.Issues.Syntaxes) {
+		if !w.referenced(ref) {
+			refs = append(refs, ref)
+		}
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+	return refs
+}
+
+// referenced reports whether the message already references the issue.
+func (w *wizard) referenced(value string) bool {
+	for _, ref := range w.config.ReferenceIssues {
+		if strings.EqualFold(ref.Issue, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateIssue accepts the issue references written in one of the configured syntaxes.
+func (w *wizard) validateIssue(input string) error {
+	_, err := issue.Parse(input, w.settings.Issues.Syntaxes)
+	return err
+}
+
+// validateDescription requires a description of at least lint.SubjectMinLength characters,
+// so the wizard never produces a message the linter rejects.
+func validateDescription(input string) error {
+	if utf8.RuneCountInString(input) < lint.SubjectMinLength {
+		return fmt.Errorf("description must have at least %d characters", lint.SubjectMinLength)
+	}
+	return nil
 }
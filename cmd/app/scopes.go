@@ -0,0 +1,168 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	commit "github.com/GiulianoPoeta99/conventional_commits_cli/internal"
+	cfg "github.com/GiulianoPoeta99/conventional_commits_cli/internal/config"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/git"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/history"
+)
+
+// runScopes implements `commit scopes list|add|remove|import`.
+func runScopes(g git.Git, args []string) error {
+	fs := flag.NewFlagSet("commit scopes", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: commit scopes list|add <scope>...|remove <scope>...|import")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Manages the scopes remembered for this repository and offered by the wizard.")
+		fmt.Fprintln(fs.Output(), "import reads the scopes of the Conventional Commits in the git history.")
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{message: err.Error()}
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return newUsageError("missing scopes action")
+	}
+
+	action, scopes := fs.Arg(0), fs.Args()[1:]
+	switch action {
+	case "list", "import":
+		if len(scopes) > 0 {
+			return newUsageError("unexpected argument %q", scopes[0])
+		}
+	case "add", "remove":
+		if len(scopes) == 0 {
+			return newUsageError("missing scope to %s", action)
+		}
+	default:
+		return newUsageError("unknown scopes action %q (expected list, add, remove or import)", action)
+	}
+
+	path, err := scopeHistoryPath()
+	if err != nil {
+		return err
+	}
+	h, err := history.Load(path)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
+	switch action {
+	case "list":
+		entries := h.Ranked(now)
+		if len(entries) == 0 {
+			fmt.Println("No scopes remembered yet; run `commit scopes import` to read them from git log.")
+			return nil
+		}
+		fmt.Printf("%-24s %6s  %s\n", "SCOPE", "USES", "LAST USED")
+		for _, entry := range entries {
+			fmt.Printf("%-24s %6d  %s\n", entry.Scope, entry.Count, history.Ago(entry.LastUsed, now))
+		}
+		return nil
+	case "import":
+		settings, err := cfg.Load()
+		if err != nil {
+			return fmt.Errorf("loading configuration: %w", err)
+		}
+		commits, uses, err := importScopes(g, settings, h)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Imported %d scope use(s) from %d commit(s)\n", uses, commits)
+	case "add":
+		for _, scope := range scopes {
+			if err := validateScope(scope); err != nil {
+				return newUsageError("%q: %v", scope, err)
+			}
+		}
+		for _, scope := range scopes {
+			if h.Add(scope, now) {
+				fmt.Printf("Added %s\n", scope)
+			} else {
+				fmt.Printf("%s is already remembered\n", scope)
+			}
+		}
+	case "remove":
+		missing := []string{}
+		for _, scope := range scopes {
+			if h.Remove(scope) {
+				fmt.Printf("Removed %s\n", scope)
+			} else {
+				missing = append(missing, scope)
+			}
+		}
+		if len(missing) > 0 {
+			if err := h.Save(path); err != nil {
+				return err
+			}
+			return fmt.Errorf("not in the scope history: %s", strings.Join(missing, ", "))
+		}
+	}
+
+	return h.Save(path)
+}
+
+// scopeHistoryPath returns the scope history file of the current repository.
+func scopeHistoryPath() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	root := cfg.FindGitRoot(dir)
+	if root == "" {
+		return "", errors.New("not inside a git repository")
+	}
+	return history.Path(root)
+}
+
+// importScopes records in h the scopes of the commits made since its last import.
+// It returns the number of commits read and of scope uses recorded.
+func importScopes(g git.Git, settings cfg.Config, h *history.History) (int, int, error) {
+	commits, err := g.Log(h.Imported, "")
+	if err != nil && h.Imported != "" {
+		// The last imported commit is gone, e.g. after a rebase: read the whole history.
+		commits, err = g.Log("", "")
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	uses := 0
+	for _, c := range commits {
+		parsed, err := commit.ParseCommitMessage(c.Message, settings.Types, settings.Emojis)
+		if err != nil || parsed.Scope == "" {
+			continue
+		}
+
+		date, _ := time.Parse(time.RFC3339, c.Date)
+		h.Use(parsed.Scope, date)
+		uses++
+	}
+
+	if len(commits) > 0 {
+		h.Imported = commits[0].Hash
+	}
+	return len(commits), uses, nil
+}
+
+// validateScope rejects scopes that would break the commit header.
+func validateScope(input string) error {
+	if strings.TrimSpace(input) == "" {
+		return errors.New("scope cannot be empty")
+	}
+	if strings.ContainsAny(input, "(),:\n") {
+		return errors.New("scope cannot contain parentheses, commas, colons or line breaks")
+	}
+	return nil
+}
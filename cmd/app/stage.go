@@ -0,0 +1,93 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	ui "github.com/GiulianoPoeta99/conventional_commits_cli/internal/ui"
+)
+
+// askStage offers to stage files before the first prompt: always with --stage, and
+// otherwise when nothing is staged yet, since committing would fail. Amending needs
+// --stage, as rewording the last commit requires no changes. Reverts, started or
+// resumed, are left alone: git stages their changes itself.
+func (w *wizard) askStage() error {
+	if !w.interactive {
+		return nil
+	}
+	if reverting, err := w.revertPending(); err != nil || reverting {
+		return err
+	}
+	if !w.opts.Stage {
+		if w.opts.Amend {
+			return nil
+		}
+		staged, err := w.git.HasStagedChanges()
+		if err != nil || staged {
+			return err
+		}
+	}
+	return w.stageFiles("Select the files to stage")
+}
+
+// restage offers to stage files again when the confirmation is reached with nothing
+// staged, instead of failing once every answer has been typed. Reverts are left alone
+// like in askStage.
+func (w *wizard) restage() error {
+	if !w.interactive || w.opts.Amend || w.revert != nil {
+		return nil
+	}
+	staged, err := w.git.HasStagedChanges()
+	if err != nil || staged {
+		return err
+	}
+	return w.stageFiles("Nothing is staged yet, select the files to commit")
+}
+
+// stageFiles asks for the files to stage among the changed and untracked ones, shown
+// with the count of added and deleted lines, and stages the chosen ones.
+func (w *wizard) stageFiles(label string) error {
+	changes, err := w.git.Unstaged()
+	if err != nil {
+		return fmt.Errorf("listing the changed files: %w", err)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	width := 0
+	for _, change := range changes {
+		width = max(width, utf8.RuneCountInString(change.Path))
+	}
+	items := []string{}
+	for _, change := range changes {
+		stat := fmt.Sprintf("+%d -%d", change.Added, change.Deleted)
+		if change.Binary {
+			stat = "binary"
+		}
+		if change.Status != "modified" {
+			stat += ", " + change.Status
+		}
+		padding := strings.Repeat(" ", width-utf8.RuneCountInString(change.Path))
+		items = append(items, fmt.Sprintf("%s%s  %s", change.Path, padding, stat))
+	}
+
+	indices, err := ui.MultiSelect(w.prompter, label, items)
+	if err != nil {
+		return fmt.Errorf("selecting the files to stage: %w", err)
+	}
+	if len(indices) == 0 {
+		return nil
+	}
+
+	paths := []string{}
+	for _, i := range indices {
+		paths = append(paths, changes[i].Path)
+	}
+	if err := w.git.Stage(paths...); err != nil {
+		return fmt.Errorf("staging: %w", err)
+	}
+	w.printf("Staged %d file(s)\n", len(paths))
+	return nil
+}
@@ -0,0 +1,193 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	commit "github.com/GiulianoPoeta99/conventional_commits_cli/internal"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/identity"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/trailer"
+	t "github.com/GiulianoPoeta99/conventional_commits_cli/internal/types"
+	ui "github.com/GiulianoPoeta99/conventional_commits_cli/internal/ui"
+)
+
+// askTrailers collects the other trailers from the --trailer flags or prompts: the
+// required trailers the previous steps did not provide, then any other one. Every
+// trailer is finally checked against the trailer rules, so the wizard never writes
+// a message the linter rejects.
+func (w *wizard) askTrailers() error {
+	if w.opts.has("trailer") || !w.interactive {
+		for _, value := range w.opts.Trailers {
+			footer, err := trailer.Split(value)
+			if err != nil {
+				return newUsageError("--trailer: %v", err)
+			}
+			if !w.hasTrailer(footer) {
+				w.config.Trailers = append(w.config.Trailers, footer)
+			}
+		}
+		if err := w.checkTrailers(); err != nil {
+			return newUsageError("%v", err)
+		}
+		return nil
+	}
+
+	// Ask for the required trailers still missing.
+	for _, rule := range w.settings.Trailers {
+		if !rule.Required || trailer.Has(commit.Footers(w.config), rule.Key) {
+			continue
+		}
+		if err := w.addTrailer(rule, trailerLabel(rule)+" (required)"); err != nil {
+			return err
+		}
+	}
+
+	add, err := ui.ConfirmSelect(w.prompter, "Do you want to add other trailers?")
+	if err != nil {
+		return fmt.Errorf("asking about trailers: %w", err)
+	}
+
+	for add {
+		key, err := w.selectTrailerKey()
+		if err != nil {
+			return fmt.Errorf("selecting trailer: %w", err)
+		}
+		if key == "" {
+			break
+		}
+
+		rule, ok := trailer.Find(w.settings.Trailers, key)
+		if !ok {
+			rule = trailer.Rule{Key: key}
+		}
+		if err := w.addTrailer(rule, trailerLabel(rule)); err != nil {
+			return err
+		}
+
+		add, err = ui.ConfirmSelect(w.prompter, "Do you want to add another trailer?")
+		if err != nil {
+			return fmt.Errorf("asking about more trailers: %w", err)
+		}
+	}
+
+	return w.checkTrailers()
+}
+
+// signoff adds the Signed-off-by trailer of the Developer Certificate of Origin when
+// --signoff or message.signoff asks for it, with the identity git commits as.
+func (w *wizard) signoff() error {
+	enabled := w.settings.Message.Signoff
+	if w.opts.has("signoff") {
+		enabled = w.opts.Signoff
+	}
+	if !enabled {
+		return nil
+	}
+
+	name, err := w.git.Config("user.name")
+	if err != nil {
+		return err
+	}
+	email, err := w.git.Config("user.email")
+	if err != nil {
+		return err
+	}
+	id, err := identity.Parse(name + " <" + email + ">")
+	if err != nil {
+		return errors.New(`signing off needs user.name and user.email: set them with git config user.name "Jane Doe" and git config user.email jane@example.com`)
+	}
+
+	// Amending a commit signed off by the same person keeps a single sign-off.
+	signoff := t.Trailer{Key: "Signed-off-by", Value: id.String()}
+	if !w.hasTrailer(signoff) {
+		w.config.Trailers = append(w.config.Trailers, signoff)
+	}
+	return nil
+}
+
+// keepTrailers asks whether to keep the other trailers of the amended commit, before
+// the sign-off and the new trailers are added.
+func (w *wizard) keepTrailers() error {
+	if !w.interactive || w.opts.has("trailer") {
+		return nil
+	}
+
+	values := []string{}
+	for _, footer := range w.config.Trailers {
+		values = append(values, footer.Key+": "+footer.Value)
+	}
+	keep, err := w.keep("trailers", values)
+	if err != nil || keep {
+		return err
+	}
+	w.config.Trailers = nil
+	return nil
+}
+
+// hasTrailer reports whether the trailer was already added, e.g. a --trailer repeating
+// the sign-off. Values naming people are compared by email.
+func (w *wizard) hasTrailer(footer t.Trailer) bool {
+	for _, other := range w.config.Trailers {
+		if !strings.EqualFold(other.Key, footer.Key) {
+			continue
+		}
+		a, errA := identity.Parse(other.Value)
+		b, errB := identity.Parse(footer.Value)
+		if other.Value == footer.Value || (errA == nil && errB == nil && a.Same(b)) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectTrailerKey asks for the key of a trailer, proposing the declared ones.
+// It returns an empty key when the user picked none.
+func (w *wizard) selectTrailerKey() (string, error) {
+	if len(w.settings.Trailers) == 0 {
+		return ui.InputWithValidation(w.prompter, "Trailer key (e.g., 'Signed-off-by')", "", trailer.ValidKey)
+	}
+
+	suggestions := []ui.Suggestion{}
+	for _, rule := range w.settings.Trailers {
+		suggestions = append(suggestions, ui.Suggestion{Value: rule.Key, Detail: rule.Description})
+	}
+	return ui.SelectSuggestion(w.prompter, "Select the trailer", "Trailer key (e.g., 'Signed-off-by')", suggestions, trailer.ValidKey)
+}
+
+// addTrailer asks for a value accepted by rule and appends the trailer.
+func (w *wizard) addTrailer(rule trailer.Rule, label string) error {
+	value, err := ui.InputWithValidation(w.prompter, label, "", rule.Validate)
+	if err != nil {
+		return fmt.Errorf("entering %s: %w", rule.Key, err)
+	}
+
+	w.config.Trailers = append(w.config.Trailers, t.Trailer{Key: rule.Key, Value: strings.TrimSpace(value)})
+	return nil
+}
+
+// checkTrailers checks every trailer of the message against the trailer rules.
+func (w *wizard) checkTrailers() error {
+	problems := trailer.Check(commit.Footers(w.config), w.settings.Trailers)
+	if len(problems) == 0 {
+		return nil
+	}
+
+	messages := []string{}
+	for _, problem := range problems {
+		message := problem.Message
+		if problem.Index < 0 {
+			message += fmt.Sprintf(" (use --trailer %s=...)", problem.Key)
+		}
+		messages = append(messages, message)
+	}
+	return fmt.Errorf("%s", strings.Join(messages, "; "))
+}
+
+// trailerLabel returns the prompt label of the value of a trailer.
+func trailerLabel(rule trailer.Rule) string {
+	if rule.Description != "" {
+		return fmt.Sprintf("%s (%s)", rule.Key, rule.Description)
+	}
+	return rule.Key
+}
@@ -0,0 +1,170 @@
+package app
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/git"
+	ui "github.com/GiulianoPoeta99/conventional_commits_cli/internal/ui"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden transcripts in testdata")
+
+// transcriptRun is one run of the wizard replaying testdata/<script>.yaml.
+type transcriptRun struct {
+	script string
+	args   []string
+}
+
+// TestTranscripts replays the answer scripts of testdata on a fake repository and
+// compares the questions asked, the answers given, the messages printed and the
+// resulting commit with the golden transcripts testdata/<name>.golden. Run with
+// -update to rewrite them.
+func TestTranscripts(tt *testing.T) {
+	testdata, err := filepath.Abs("testdata")
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		config string
+		// setup prepares the repository; without it main.go is staged before every run.
+		setup func(tt *testing.T, g *git.Fake)
+		runs  []transcriptRun
+	}{
+		{
+			// Every validated prompt rejects an answer before accepting the next one.
+			name:   "retries",
+			config: "trailers:\n  - key: Ticket\n    pattern: \"^[A-Z]+-[0-9]+$\"\n    required: true\n",
+			runs:   []transcriptRun{{script: "retries"}},
+		},
+		{
+			name: "menu",
+			runs: []transcriptRun{{script: "menu", args: []string{"--type", "fix", "--description", "stop the crash", "--body", "", "--breaking=false"}}},
+		},
+		{
+			name: "draft",
+			runs: []transcriptRun{
+				{script: "draft", args: []string{"--type", "fix", "--description", "stop the crash", "--body", "", "--breaking=false"}},
+				{script: "draft-resume"},
+			},
+		},
+		{
+			// The commits to revert are picked from the history.
+			name: "revert",
+			setup: func(tt *testing.T, g *git.Fake) {
+				commitAll(tt, g, "feat(api): add pagination\n\nCloses: #12", "fix: stop the crash")
+			},
+			runs: []transcriptRun{{script: "revert"}},
+		},
+		{
+			// Amending asks whether to keep each part of the message of HEAD.
+			name: "amend",
+			setup: func(tt *testing.T, g *git.Fake) {
+				commitAll(tt, g, "feat(api): add pagination\n\nAdds page and size parameters.\n\n"+
+					"Reviewed-by: Ann <ann@example.com>\nCo-authored-by: Bob <bob@example.com>\nRefs: #12")
+				g.Staged = []string{"api.go"}
+			},
+			runs: []transcriptRun{{script: "amend", args: []string{"--amend"}}},
+		},
+		{
+			// Nothing is staged, so the files are picked before the type is inferred.
+			name: "staging",
+			setup: func(tt *testing.T, g *git.Fake) {
+				g.Changes = []git.Change{
+					{Path: "api.go", Status: "modified", Added: 3, Deleted: 1},
+					{Path: "docs/api.md", Status: "untracked", Added: 10},
+					{Path: "docs/guide.md", Status: "modified", Added: 2, Deleted: 2},
+				}
+			},
+			runs: []transcriptRun{{script: "staging"}},
+		},
+		{
+			// Reviewers and co-authors are picked among the authors of the history.
+			name: "people",
+			setup: func(tt *testing.T, g *git.Fake) {
+				g.Author = "Ann Lee <ann@example.com>"
+				commitAll(tt, g, "feat: add pagination")
+				g.Author = "Bob Stone <bob@example.com>"
+				commitAll(tt, g, "fix: stop the crash\n\nCo-authored-by: Eve Park <eve@example.com>")
+				g.Staged = []string{"main.go"}
+			},
+			runs: []transcriptRun{{script: "people", args: []string{"--type", "fix", "--description", "stop the crash", "--body", "", "--breaking=false"}}},
+		},
+		{
+			// Configured trailers are offered by name, and the sign-off names the git user.
+			name: "trailers",
+			config: "message:\n  signoff: true\n" +
+				"trailers:\n" +
+				"  - key: Ticket\n    pattern: \"^[A-Z]+-[0-9]+$\"\n    required: true\n" +
+				"  - key: Release-note\n    description: the line for the release notes\n",
+			setup: func(tt *testing.T, g *git.Fake) {
+				g.Settings["user.name"] = "Jane Doe"
+				g.Settings["user.email"] = "jane@example.com"
+				g.Staged = []string{"main.go"}
+			},
+			runs: []transcriptRun{{script: "trailers", args: []string{"--type", "feat", "--description", "add pagination", "--body", "", "--breaking=false"}}},
+		},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			g := testRepo(tt)
+			if test.config != "" {
+				if err := os.WriteFile(".commitrc.yaml", []byte(test.config), 0o644); err != nil {
+					tt.Fatal(err)
+				}
+			}
+
+			if test.setup != nil {
+				test.setup(tt, g)
+			}
+
+			transcript := &bytes.Buffer{}
+			for _, run := range test.runs {
+				if test.setup == nil {
+					g.Staged = []string{"main.go"}
+				}
+				script, err := ui.LoadScript(filepath.Join(testdata, run.script+".yaml"))
+				if err != nil {
+					tt.Fatal(err)
+				}
+				script.Transcript = transcript
+				w, _, _ := newTestWizard(tt, g, run.args)
+				w.prompter, w.interactive, w.out = script, true, transcript
+
+				fmt.Fprintf(transcript, "=== %s\n", run.script)
+				if err := runWizard(tt, w, script); err != nil {
+					fmt.Fprintf(transcript, "error: %v\n", err)
+				}
+			}
+			if len(g.History) > 0 {
+				fmt.Fprintf(transcript, "=== HEAD\n%s\n", g.History[0].Message)
+			}
+
+			// The repository is a new temporary directory on every run.
+			got := strings.ReplaceAll(transcript.String(), filepath.Dir(g.Dir), "$REPO")
+
+			golden := filepath.Join(testdata, test.name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					tt.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				tt.Fatal(err)
+			}
+			if got != string(want) {
+				tt.Errorf("transcript differs from %s:\n%s", golden, got)
+			}
+		})
+	}
+}
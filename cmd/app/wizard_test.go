@@ -0,0 +1,531 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/git"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/history"
+	ui "github.com/GiulianoPoeta99/conventional_commits_cli/internal/ui"
+)
+
+// testRepo returns a fake repository whose git directory is a temporary directory,
+// entered as the working directory so that the configuration and the scope history
+// are read from empty temporary locations.
+func testRepo(tt *testing.T) *git.Fake {
+	tt.Helper()
+	root := tt.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0o755); err != nil {
+		tt.Fatal(err)
+	}
+	tt.Chdir(root)
+	tt.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "config"))
+	for _, name := range []string{"GIT_EDITOR", "VISUAL", "EDITOR"} {
+		tt.Setenv(name, "")
+	}
+
+	g := git.NewFake()
+	g.Dir = filepath.Join(root, ".git")
+	return g
+}
+
+// newTestWizard returns a wizard on g parsing args. With answers it replays them and
+// records the transcript; without, it runs as if stdin were not a terminal.
+func newTestWizard(tt *testing.T, g git.Git, args []string, answers ...string) (*wizard, *ui.Scripted, *bytes.Buffer) {
+	tt.Helper()
+	opts, err := parseOptions(args)
+	if err != nil {
+		tt.Fatalf("parseOptions(%q): %v", args, err)
+	}
+
+	w := &wizard{git: g, opts: opts}
+	transcript := &bytes.Buffer{}
+	if answers == nil {
+		return w, nil, transcript
+	}
+
+	scripted := []ui.Answer{}
+	for _, answer := range answers {
+		scripted = append(scripted, ui.Answer{Value: answer})
+	}
+	script := ui.NewScripted(scripted...)
+	script.Transcript = transcript
+	w.prompter, w.interactive, w.out = script, true, transcript
+	return w, script, transcript
+}
+
+// allFields returns the flags answering every field of the wizard, so that only the
+// confirmation menu is left to answer.
+func allFields() []string {
+	return []string{
+		"--type", "fix", "--scope", "cli", "--emoji", "bug", "--description", "stop the crash",
+		"--body", "", "--breaking=false", "--ref", "#1", "--trailer", "X-Y=z",
+		"--reviewer", "Ann <ann@example.com>", "--co-author", "Bob <bob@example.com>",
+	}
+}
+
+// runWizard runs the wizard and checks that every answer was used.
+func runWizard(tt *testing.T, w *wizard, script *ui.Scripted) error {
+	tt.Helper()
+	err := w.run()
+	if err == nil && script != nil && script.Remaining() > 0 {
+		tt.Fatalf("%d answers left unused", script.Remaining())
+	}
+	return err
+}
+
+// TestAmendWithoutChanges checks that amending with no changes keeps the message
+// byte for byte, including a body paragraph shaped like a trailer.
+func TestAmendWithoutChanges(tt *testing.T) {
+	message := "feat(api): add pagination\n\nNote: this is the body paragraph.\n\nRefs: #12"
+
+	g := testRepo(tt)
+	g.Staged = []string{"api.go"}
+	if err := g.Commit(message, git.CommitOptions{}); err != nil {
+		tt.Fatal(err)
+	}
+
+	w, script, _ := newTestWizard(tt, g, []string{"--amend", "--yes"})
+	if err := runWizard(tt, w, script); err != nil {
+		tt.Fatal(err)
+	}
+
+	if len(g.History) != 1 {
+		tt.Fatalf("history has %d commits, want 1", len(g.History))
+	}
+	if got := g.History[0].Message; got != message {
+		tt.Errorf("amended message:\n%s\nwant:\n%s", got, message)
+	}
+	if !g.LastCommitOptions.Amend {
+		tt.Error("the commit was not amended")
+	}
+}
+
+// TestBranchReferencesUnattended checks that the references found in the branch name
+// are only added without a terminal when the configuration opts in.
+func TestBranchReferencesUnattended(tt *testing.T) {
+	for _, optIn := range []bool{false, true} {
+		g := testRepo(tt)
+		g.Branch = "fix/567-crash"
+		g.Staged = []string{"main.go"}
+		if optIn {
+			config := "issues:\n  fromBranchUnattended: true\n"
+			if err := os.WriteFile(".commitrc.yaml", []byte(config), 0o644); err != nil {
+				tt.Fatal(err)
+			}
+		}
+
+		w, script, _ := newTestWizard(tt, g, []string{"--type", "fix", "--description", "stop the crash"})
+		if err := runWizard(tt, w, script); err != nil {
+			tt.Fatal(err)
+		}
+
+		want := "fix: stop the crash"
+		if optIn {
+			want += "\n\nRefs: #567"
+		}
+		if got := g.History[0].Message; got != want {
+			tt.Errorf("with fromBranchUnattended %v, message %q, want %q", optIn, got, want)
+		}
+	}
+}
+
+// TestCommitInteractive walks through every prompt of a new commit.
+func TestCommitInteractive(tt *testing.T) {
+	g := testRepo(tt)
+	g.Staged = []string{"internal/api/list.go"}
+
+	w, script, _ := newTestWizard(tt, g, nil,
+		"feat",                            // type
+		"none",                            // scope
+		"no",                              // emoji
+		"add pagination",                  // description
+		"Pages hold 50 items.",            // body
+		"yes",                             // breaking
+		"the list endpoints return pages", // reason
+		"no",                              // reviewers
+		"yes",                             // co-authors
+		"custom",
+		"Bob <bob@example.com>",
+		"no",
+		"yes", // issues
+		"#12",
+		"Closes", // relation
+		"no",
+		"no", // trailers
+		"commit",
+	)
+	if err := runWizard(tt, w, script); err != nil {
+		tt.Fatal(err)
+	}
+
+	want := "feat!: add pagination\n\n" +
+		"Pages hold 50 items.\n\n" +
+		"BREAKING CHANGE: the list endpoints return pages\n\n" +
+		"Co-authored-by: Bob <bob@example.com>\n" +
+		"Closes: #12"
+	if got := g.History[0].Message; got != want {
+		tt.Errorf("message:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// TestAmendWithFlags checks that flags change single values of the amended commit
+// and add to its lists.
+func TestAmendWithFlags(tt *testing.T) {
+	g := testRepo(tt)
+	g.Staged = []string{"api.go"}
+	if err := g.Commit("feat(api): add pagination\n\nCo-authored-by: Ann <ann@example.com>", git.CommitOptions{}); err != nil {
+		tt.Fatal(err)
+	}
+
+	w, script, _ := newTestWizard(tt, g, []string{"--amend", "--yes", "--description", "paginate lists", "--co-author", "Bob <bob@example.com>"})
+	if err := runWizard(tt, w, script); err != nil {
+		tt.Fatal(err)
+	}
+
+	want := "feat(api): paginate lists\n\nCo-authored-by: Ann <ann@example.com>\nCo-authored-by: Bob <bob@example.com>"
+	if len(g.History) != 1 || g.History[0].Message != want {
+		tt.Errorf("history %#v, want a single commit with message %q", g.History, want)
+	}
+}
+
+// TestAmendPushed checks that amending a pushed commit needs --force.
+func TestAmendPushed(tt *testing.T) {
+	g := testRepo(tt)
+	g.Staged = []string{"api.go"}
+	if err := g.Commit("feat: add pagination", git.CommitOptions{}); err != nil {
+		tt.Fatal(err)
+	}
+	g.UpstreamRef, g.UpstreamHash = "origin/main", g.History[0].Hash
+
+	w, script, _ := newTestWizard(tt, g, []string{"--amend", "--yes", "--description", "paginate lists"})
+	if err := runWizard(tt, w, script); err == nil || !strings.Contains(err.Error(), "--force") {
+		tt.Fatalf("amending a pushed commit: got %v, want an error asking for --force", err)
+	}
+
+	w, script, _ = newTestWizard(tt, g, []string{"--amend", "--force", "--yes", "--description", "paginate lists"})
+	if err := runWizard(tt, w, script); err != nil {
+		tt.Fatal(err)
+	}
+	if got := g.History[0].Message; got != "feat: paginate lists" {
+		tt.Errorf("message %q, want the amended one", got)
+	}
+}
+
+// commitAll records the messages in g, oldest first.
+func commitAll(tt *testing.T, g *git.Fake, messages ...string) {
+	tt.Helper()
+	for i, message := range messages {
+		g.Staged = []string{fmt.Sprintf("file%d.go", i)}
+		if err := g.Commit(message, git.CommitOptions{}); err != nil {
+			tt.Fatal(err)
+		}
+	}
+}
+
+// TestRevert checks that reverted commits are applied newest first and described in
+// the message, with their references carried over.
+func TestRevert(tt *testing.T) {
+	g := testRepo(tt)
+	commitAll(tt, g, "feat(api): add pagination\n\nCloses: #12", "fix: stop the crash")
+	newest, oldest := g.History[0], g.History[1]
+
+	w, script, _ := newTestWizard(tt, g, []string{"--revert", oldest.Hash, "--revert", newest.Hash, "--yes"})
+	if err := runWizard(tt, w, script); err != nil {
+		tt.Fatal(err)
+	}
+
+	want := "revert: fix: stop the crash and 1 more\n\n" +
+		"This reverts commit " + newest.Hash + ".\n" +
+		"This reverts commit " + oldest.Hash + ".\n\n" +
+		"Refs: #12"
+	if got := g.History[0].Message; got != want {
+		tt.Errorf("message:\n%s\nwant:\n%s", got, want)
+	}
+	if _, err := os.Stat(filepath.Join(g.Dir, revertDraftName)); !errors.Is(err, os.ErrNotExist) {
+		tt.Errorf("the revert draft was left behind: %v", err)
+	}
+}
+
+// TestRevertConflict checks that a revert stopped by conflicts pauses the wizard and
+// resumes once they are resolved.
+func TestRevertConflict(tt *testing.T) {
+	g := testRepo(tt)
+	commitAll(tt, g, "feat: add pagination", "fix: stop the crash")
+	hash := g.History[1].Hash
+	g.Conflicts[hash] = []string{"api.go"}
+
+	w, script, _ := newTestWizard(tt, g, []string{"--revert", hash, "--yes"})
+	var exit *exitError
+	if err := runWizard(tt, w, script); !errors.As(err, &exit) || exit.code != 1 {
+		tt.Fatalf("got %v, want to stop with status 1", err)
+	}
+	if len(g.History) != 2 {
+		tt.Fatalf("a commit was created despite the conflicts")
+	}
+
+	// Resolving the conflicts and running the wizard again finishes the revert.
+	g.Unmerged = nil
+	w, script, _ = newTestWizard(tt, g, []string{"--yes"})
+	if err := runWizard(tt, w, script); err != nil {
+		tt.Fatal(err)
+	}
+	if got := g.History[0].Header(); got != "revert: feat: add pagination" {
+		tt.Errorf("header %q, want the revert of the conflicting commit", got)
+	}
+}
+
+// TestStage checks that the files chosen in the staging step are staged, and that the
+// step is offered again before the confirmation when nothing was chosen.
+func TestStage(tt *testing.T) {
+	g := testRepo(tt)
+	g.Changes = []git.Change{
+		{Path: "api.go", Status: "modified", Added: 3, Deleted: 1},
+		{Path: "docs/api.md", Status: "untracked", Added: 10},
+	}
+	args := []string{"--type", "docs", "--description", "document the API", "--body", "", "--breaking=false"}
+
+	w, script, _ := newTestWizard(tt, g, args,
+		"done", // nothing picked at first
+		"",     // scope
+		"no",   // emoji
+		"no",   // reviewers
+		"no",   // co-authors
+		"no",   // issues
+		"no",   // trailers
+		"docs/api.md",
+		"done",
+		"commit",
+	)
+	if err := runWizard(tt, w, script); err != nil {
+		tt.Fatal(err)
+	}
+
+	if len(g.History) != 1 || g.History[0].Message != "docs: document the API" {
+		tt.Fatalf("history %#v, want the docs commit", g.History)
+	}
+	if len(g.Changes) != 1 || g.Changes[0].Path != "api.go" {
+		tt.Errorf("unstaged changes %#v, want only api.go", g.Changes)
+	}
+}
+
+// TestFixup checks a fixup! commit folded into its target with --autosquash.
+func TestFixup(tt *testing.T) {
+	g := testRepo(tt)
+	commitAll(tt, g, "feat(api): add pagination", "fix: stop the crash")
+	target := g.History[1]
+	g.Staged = []string{"api.go"}
+
+	if err := runFixup(g, []string{"--target", target.Hash, "--squash", "--message", "Count from one."}); err != nil {
+		tt.Fatal(err)
+	}
+	if got := g.History[0].Message; got != "squash! feat(api): add pagination\n\nCount from one." {
+		tt.Errorf("fixup message %q", got)
+	}
+
+	if err := g.Autosquash(target.Hash); err != nil {
+		tt.Fatal(err)
+	}
+	headers := []string{}
+	for _, c := range g.History {
+		headers = append(headers, c.Header())
+	}
+	if !reflect.DeepEqual(headers, []string{"fix: stop the crash", "feat(api): add pagination"}) {
+		tt.Errorf("headers after the autosquash %q", headers)
+	}
+	if got := g.History[1].Message; got != "feat(api): add pagination\n\nCount from one." {
+		tt.Errorf("squashed message %q", got)
+	}
+}
+
+// TestFixupAutosquashPushed checks that folding into a pushed commit needs --force.
+func TestFixupAutosquashPushed(tt *testing.T) {
+	g := testRepo(tt)
+	commitAll(tt, g, "feat(api): add pagination")
+	g.UpstreamRef, g.UpstreamHash = "origin/main", g.History[0].Hash
+	g.Staged = []string{"api.go"}
+
+	err := runFixup(g, []string{"--target", "HEAD", "--autosquash"})
+	if err == nil || !strings.Contains(err.Error(), "--force") {
+		tt.Fatalf("got %v, want an error asking for --force", err)
+	}
+	if len(g.History) != 1 {
+		tt.Errorf("a fixup commit was created")
+	}
+}
+
+// TestConfirmCommitFailure checks that a failed commit comes back to the menu, where
+// the message can still be committed once the problem is fixed.
+func TestConfirmCommitFailure(tt *testing.T) {
+	g := testRepo(tt)
+	g.Staged = []string{"main.go"}
+	g.Errors = map[string]error{"Commit": errors.New("pre-commit hook failed")}
+
+	w, script, _ := newTestWizard(tt, g, allFields(),
+		"commit",
+		"commit",
+	)
+	// The first attempt fails, then the problem is fixed before the second one.
+	w.prompter = &hookPrompter{Prompter: w.prompter, before: func() { g.Errors = nil }, after: 1}
+	if err := runWizard(tt, w, script); err != nil {
+		tt.Fatal(err)
+	}
+	if len(g.History) != 1 || g.History[0].Header() != "fix(cli): :bug: stop the crash" {
+		tt.Errorf("history %#v, want the commit made on the second attempt", g.History)
+	}
+}
+
+// hookPrompter runs before ahead of the question following the first after ones.
+type hookPrompter struct {
+	ui.Prompter
+	before func()
+	after  int
+}
+
+// Select runs the hook when due, then asks the wrapped prompter.
+func (p *hookPrompter) Select(label string, items []string, opts ui.SelectOptions) (int, error) {
+	if p.after == 0 {
+		p.before()
+	}
+	p.after--
+	return p.Prompter.Select(label, items, opts)
+}
+
+// TestConfirmEditorFields checks that the fields follow the message edited in the
+// editor, so that the scope history records the edited scope and co-authors.
+func TestConfirmEditorFields(tt *testing.T) {
+	g := testRepo(tt)
+	g.Staged = []string{"main.go"}
+	tt.Setenv("EDITOR", `sed -i -e 's/(cli)/(api)/' -e 's/Bob <bob@example.com>/Eve <eve@example.com>/'`)
+
+	w, script, _ := newTestWizard(tt, g, allFields(),
+		"editor",
+		"commit",
+	)
+	if err := runWizard(tt, w, script); err != nil {
+		tt.Fatal(err)
+	}
+
+	if got := g.History[0].Header(); got != "fix(api): :bug: stop the crash" {
+		tt.Errorf("header = %q, want the edited scope", got)
+	}
+	if w.config.Scope != "api" || !reflect.DeepEqual(w.config.CoAuthors, []string{"Eve <eve@example.com>"}) {
+		tt.Errorf("scope %q and co-authors %q, want the edited ones", w.config.Scope, w.config.CoAuthors)
+	}
+	scopes := []string{}
+	for _, entry := range w.history.Scopes {
+		scopes = append(scopes, entry.Scope)
+	}
+	if !reflect.DeepEqual(scopes, []string{"api"}) {
+		tt.Errorf("history scopes %q, want the edited scope only", scopes)
+	}
+}
+
+// TestConfirmCopyInterrupt checks that Ctrl+C in the copy submenu stops the wizard
+// instead of coming back to the menu.
+func TestConfirmCopyInterrupt(tt *testing.T) {
+	g := testRepo(tt)
+	g.Staged = []string{"main.go"}
+
+	w, script, _ := newTestWizard(tt, g, allFields(),
+		"copy",
+	)
+	script.Answers = append(script.Answers, ui.Answer{Interrupt: true})
+
+	if err := runWizard(tt, w, script); !ui.IsInterrupt(err) {
+		tt.Fatalf("run() = %v, want the interrupt", err)
+	}
+	if len(g.History) != 0 {
+		tt.Errorf("history %#v, want no commit", g.History)
+	}
+}
+
+// TestPeopleFlagsExact checks that the people flags complete exact names and emails
+// only, since nothing shows the completed identity before committing.
+func TestPeopleFlagsExact(tt *testing.T) {
+	tests := []struct {
+		reviewer string
+		want     string
+	}{
+		{reviewer: "jane@example.com", want: "Reviewed-by: Jane Doe <jane@example.com>"},
+		{reviewer: "Jane Doe", want: "Reviewed-by: Jane Doe <jane@example.com>"},
+		{reviewer: "Jane"},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.reviewer, func(tt *testing.T) {
+			g := testRepo(tt)
+			g.Staged = []string{"main.go"}
+			if err := os.WriteFile(".mailmap", []byte("Jane Doe <jane@example.com> <jd@example.com>\n"), 0o644); err != nil {
+				tt.Fatal(err)
+			}
+
+			w, _, _ := newTestWizard(tt, g, []string{"--type", "fix", "--description", "stop the crash", "--reviewer", test.reviewer})
+			err := w.run()
+			if test.want == "" {
+				if err == nil || !strings.Contains(err.Error(), "--reviewer") {
+					tt.Fatalf("run() = %v, want an error about --reviewer", err)
+				}
+				return
+			}
+			if err != nil {
+				tt.Fatal(err)
+			}
+			if message := g.History[0].Message; !strings.Contains(message, test.want) {
+				tt.Errorf("message:\n%s\nwant %q", message, test.want)
+			}
+		})
+	}
+}
+
+// TestRevertSkipsStaging checks that reverting, or resuming a revert, does not offer
+// to stage the unrelated changes of the working tree.
+func TestRevertSkipsStaging(tt *testing.T) {
+	g := testRepo(tt)
+	commitAll(tt, g, "feat: add pagination", "fix: stop the crash")
+	hash := g.History[1].Hash
+	g.Conflicts[hash] = []string{"api.go"}
+	g.Changes = []git.Change{{Path: "notes.txt"}}
+
+	// Staging would ask a question, which the empty script cannot answer.
+	w, _, _ := newTestWizard(tt, g, []string{"--revert", hash, "--yes"})
+	w.prompter, w.interactive = ui.NewScripted(), true
+	var exit *exitError
+	if err := w.run(); !errors.As(err, &exit) || exit.code != 1 {
+		tt.Fatalf("got %v, want to stop with status 1 on the conflicts", err)
+	}
+
+	// Resolving the conflicts may leave nothing staged.
+	g.Unmerged, g.Staged = nil, nil
+	w, _, _ = newTestWizard(tt, g, nil)
+	w.prompter, w.interactive = ui.NewScripted(), true
+	if err := w.askStage(); err != nil {
+		tt.Fatalf("resuming the revert: %v", err)
+	}
+}
+
+// TestLoadHistorySavesImport checks that the scopes imported from git log are saved,
+// so that the next run does not read the same commits again.
+func TestLoadHistorySavesImport(tt *testing.T) {
+	g := testRepo(tt)
+	commitAll(tt, g, "feat(api): add pagination", "fix(cli): stop the crash")
+
+	w, _, _ := newTestWizard(tt, g, nil)
+	if err := w.loadHistory(); err != nil {
+		tt.Fatal(err)
+	}
+
+	saved, err := history.Load(w.historyPath)
+	if err != nil {
+		tt.Fatal(err)
+	}
+	if saved.Imported != g.History[0].Hash || len(saved.Scopes) != 2 {
+		tt.Errorf("saved history %#v, want both scopes imported up to %s", saved, g.History[0].Hash)
+	}
+}
@@ -2,7 +2,11 @@ module github.com/GiulianoPoeta99/conventional_commits_cli
 
 go 1.24.1
 
-require github.com/manifoldco/promptui v0.9.0
+require (
+	github.com/manifoldco/promptui v0.9.0
+	golang.org/x/term v0.32.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/chzyer/readline v1.5.1 // indirect
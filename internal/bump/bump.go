@@ -0,0 +1,115 @@
+// Package bump computes the next semantic version from the Conventional Commits
+// made since the latest release.
+package bump
+
+import (
+	"fmt"
+
+	commit "github.com/GiulianoPoeta99/conventional_commits_cli/internal"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/git"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/lint"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/semver"
+	t "github.com/GiulianoPoeta99/conventional_commits_cli/internal/types"
+)
+
+// Reason is a commit that calls for a version increment.
+type Reason struct {
+	Commit git.Commit
+	Config t.CommitConfig
+	Level  semver.Level
+}
+
+// Options configures how commits translate into version increments.
+type Options struct {
+	// Types and Emojis are the catalogues used to parse the messages.
+	Types  []t.CommitType
+	Emojis []t.Emoji
+	// Levels maps a commit type code to its increment; other types trigger none.
+	Levels map[string]semver.Level
+}
+
+// ParseLevels converts the configured level names into levels.
+func ParseLevels(names map[string]string) (map[string]semver.Level, error) {
+	levels := map[string]semver.Level{}
+	for code, name := range names {
+		level, err := semver.ParseLevel(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", code, err)
+		}
+		levels[code] = level
+	}
+	return levels, nil
+}
+
+// Versions returns the versions carried by the tags with the given prefix, and the
+// highest release among them. found is false when no release tag exists.
+func Versions(tags []string, prefix string) (versions []semver.Version, latest semver.Version, found bool) {
+	for _, tag := range tags {
+		version, err := semver.Parse(tag, prefix)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, version)
+
+		if !version.IsPrerelease() && (!found || semver.Compare(version, latest) > 0) {
+			latest = version
+			found = true
+		}
+	}
+	return versions, latest, found
+}
+
+// Decide returns the increment required by the commits and the commits that require it.
+// Breaking changes trigger a major increment whatever their type; commits that are not
+// Conventional Commits are ignored.
+func Decide(commits []git.Commit, opts Options) (semver.Level, []Reason) {
+	level := semver.None
+	reasons := []Reason{}
+
+	for _, c := range commits {
+		if lint.IsIgnored(c.Message) {
+			continue
+		}
+
+		config, err := commit.ParseCommitMessage(c.Message, opts.Types, opts.Emojis)
+		if err != nil {
+			continue
+		}
+
+		commitLevel := opts.Levels[config.Type.Code]
+		if config.Breaking {
+			commitLevel = semver.Major
+		}
+		if commitLevel == semver.None {
+			continue
+		}
+
+		reasons = append(reasons, Reason{Commit: c, Config: config, Level: commitLevel})
+		if commitLevel > level {
+			level = commitLevel
+		}
+	}
+
+	return level, reasons
+}
+
+// Next returns the version following latest for the given increment.
+// With a channel such as "rc", the result is the next "channel.N" pre-release of that
+// version, numbered after the pre-releases already present in existing.
+func Next(latest semver.Version, level semver.Level, channel string, existing []semver.Version) semver.Version {
+	next := latest.Bump(level)
+	if channel == "" {
+		return next
+	}
+
+	number := 1
+	for _, version := range existing {
+		versionChannel, versionNumber := version.Channel()
+		if version.SameRelease(next) && versionChannel == channel && versionNumber >= number {
+			number = versionNumber + 1
+		}
+	}
+
+	next.Prerelease = fmt.Sprintf("%s.%d", channel, number)
+	return next
+}
@@ -0,0 +1,82 @@
+package bump
+
+import (
+	"testing"
+
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/git"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/semver"
+	t "github.com/GiulianoPoeta99/conventional_commits_cli/internal/types"
+)
+
+// TestNext checks the release versions and the numbering of the pre-release channels,
+// which continues after the pre-releases already tagged for the same version.
+func TestNext(tt *testing.T) {
+	tags := []string{"v1.1.0", "v1.2.0-rc.1", "v1.2.0-rc.2", "v1.2.0-beta.1", "v2.0.0-rc.5", "v1.2.0-rc.x", "v1.0.0"}
+	versions, latest, found := Versions(tags, "")
+	if !found || latest.String() != "v1.1.0" {
+		tt.Fatalf("Versions() latest = %s, %v, want v1.1.0", latest, found)
+	}
+
+	tests := []struct {
+		level   semver.Level
+		channel string
+		want    string
+	}{
+		{semver.Minor, "", "v1.2.0"},
+		{semver.Patch, "", "v1.1.1"},
+		{semver.Minor, "rc", "v1.2.0-rc.3"},
+		{semver.Minor, "beta", "v1.2.0-beta.2"},
+		{semver.Minor, "alpha", "v1.2.0-alpha.1"},
+		{semver.Patch, "rc", "v1.1.1-rc.1"},
+		{semver.Major, "rc", "v2.0.0-rc.6"},
+	}
+	for _, test := range tests {
+		if got := Next(latest, test.level, test.channel, versions).String(); got != test.want {
+			tt.Errorf("Next(%s, %q) = %q, want %q", test.level, test.channel, got, test.want)
+		}
+	}
+}
+
+// TestVersions checks that tags of other prefixes and pre-releases are not taken for
+// the latest release.
+func TestVersions(tt *testing.T) {
+	tags := []string{"api/v1.0.0", "api/v1.1.0-rc.1", "web/v3.0.0", "v9.0.0", "api/v0.9.0"}
+	versions, latest, found := Versions(tags, "api/")
+	if !found || latest.String() != "api/v1.0.0" || len(versions) != 3 {
+		tt.Errorf("Versions() = %v, %s, %v, want three versions and api/v1.0.0", versions, latest, found)
+	}
+
+	if _, _, found := Versions([]string{"api/v1.0.0-rc.1"}, "api/"); found {
+		tt.Error("Versions() found a release among pre-releases only")
+	}
+}
+
+// TestDecide checks the increment called for by the commits.
+func TestDecide(tt *testing.T) {
+	opts := Options{
+		Types:  []t.CommitType{{Code: "feat"}, {Code: "fix"}, {Code: "docs"}},
+		Levels: map[string]semver.Level{"feat": semver.Minor, "fix": semver.Patch},
+	}
+	tests := []struct {
+		messages []string
+		want     semver.Level
+		reasons  int
+	}{
+		{[]string{"docs: typo", "Update README"}, semver.None, 0},
+		{[]string{"fix: crash", "docs: typo"}, semver.Patch, 1},
+		{[]string{"fix: crash", "feat: pages"}, semver.Minor, 2},
+		{[]string{"docs!: drop the old guide", "feat: pages"}, semver.Major, 2},
+		{[]string{"fix: crash\n\nBREAKING CHANGE: exits with 2"}, semver.Major, 1},
+		{[]string{"fixup! feat: pages", "Merge branch 'x'"}, semver.None, 0},
+	}
+	for _, test := range tests {
+		commits := []git.Commit{}
+		for _, message := range test.messages {
+			commits = append(commits, git.Commit{Message: message})
+		}
+		level, reasons := Decide(commits, opts)
+		if level != test.want || len(reasons) != test.reasons {
+			tt.Errorf("Decide(%q) = %s with %d reasons, want %s with %d", test.messages, level, len(reasons), test.want, test.reasons)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Package changelog builds release notes from the Conventional Commit history.
+package changelog
+
+import (
+	"strings"
+
+	commit "github.com/GiulianoPoeta99/conventional_commits_cli/internal"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/git"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/issue"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/lint"
+	t "github.com/GiulianoPoeta99/conventional_commits_cli/internal/types"
+)
+
+// Link is a piece of text with an optional URL.
+type Link struct {
+	Text string `json:"text"`
+	URL  string `json:"url,omitempty"`
+}
+
+// Entry is a commit listed in the changelog.
+type Entry struct {
+	Hash      string         `json:"hash"`
+	ShortHash string         `json:"shortHash"`
+	URL       string         `json:"url,omitempty"`
+	Author    string         `json:"author"`
+	Date      string         `json:"date"`
+	Commit    t.CommitConfig `json:"commit"`
+	Refs      []Link         `json:"refs,omitempty"`
+}
+
+// Section groups the entries of one commit type.
+type Section struct {
+	Type    t.CommitType `json:"type"`
+	Entries []Entry      `json:"entries"`
+}
+
+// Release holds the changes between two revisions.
+type Release struct {
+	Version  string    `json:"version"`
+	Date     string    `json:"date"`
+	From     string    `json:"from,omitempty"`
+	To       string    `json:"to"`
+	Breaking []Entry   `json:"breaking"`
+	Sections []Section `json:"sections"`
+	// Skipped counts the commits that are not Conventional Commits.
+	Skipped int `json:"skipped"`
+	// Unknown counts the commits whose type is not in the catalogue. They have no
+	// section, and are only listed among the breaking changes.
+	Unknown int `json:"unknown"`
+}
+
+// Options configures how commits are turned into a release.
+type Options struct {
+	// Types is the commit type catalogue; it defines the order of the sections.
+	Types []t.CommitType
+	// Emojis is the catalogue used to recognise emojis in descriptions.
+	Emojis []t.Emoji
+	// Hidden lists the commit type codes left out of the sections.
+	Hidden []string
+	// IssueURL links "#123" and "PROJ-123" references; "{id}" is replaced by the number or key.
+	IssueURL string
+	// CommitURL links commits; "{hash}" is replaced by the full hash.
+	CommitURL string
+}
+
+// Build parses the commits, newest first, and groups them by commit type.
+// Breaking changes are listed in their own section even when their type is hidden or
+// unknown.
+func Build(commits []git.Commit, opts Options) Release {
+	release := Release{Breaking: []Entry{}, Sections: []Section{}}
+	byType := map[string][]Entry{}
+
+	for _, c := range commits {
+		if lint.IsIgnored(c.Message) {
+			continue
+		}
+
+		config, err := commit.ParseCommitMessage(c.Message, opts.Types, opts.Emojis)
+		if err != nil {
+			release.Skipped++
+			continue
+		}
+
+		entry := Entry{
+			Hash:      c.Hash,
+			ShortHash: c.ShortHash(),
+			Author:    c.Author,
+			Date:      c.Date,
+			Commit:    config,
+		}
+		if opts.CommitURL != "" {
+			entry.URL = strings.ReplaceAll(opts.CommitURL, "{hash}", c.Hash)
+		}
+		for _, ref := range config.ReferenceIssues {
+			entry.Refs = append(entry.Refs, issueLink(ref.Issue, opts.IssueURL))
+		}
+
+		if config.Breaking {
+			release.Breaking = append(release.Breaking, entry)
+		}
+		if !knownType(opts.Types, config.Type.Code) {
+			release.Unknown++
+			continue
+		}
+		byType[config.Type.Code] = append(byType[config.Type.Code], entry)
+	}
+
+	// Order the sections as the catalogue does, leaving hidden types out.
+	for _, commitType := range opts.Types {
+		entries := byType[commitType.Code]
+		if len(entries) == 0 || contains(opts.Hidden, commitType.Code) {
+			continue
+		}
+		release.Sections = append(release.Sections, Section{Type: commitType, Entries: entries})
+	}
+
+	return release
+}
+
+// issueLink builds the link of an issue reference: URLs link to themselves, and
+// "#123" and tracker keys such as "PROJ-123" use the URL template.
+func issueLink(text, template string) Link {
+	link := Link{Text: text}
+
+	ref, err := issue.Parse(text, nil)
+	switch {
+	case err != nil:
+	case ref.Syntax == "url":
+		link.URL = ref.ID
+	case (ref.Syntax == "github" || ref.Syntax == "jira") && template != "":
+		link.URL = strings.ReplaceAll(template, "{id}", ref.ID)
+	}
+	return link
+}
+
+// knownType reports whether the catalogue has the type code.
+func knownType(types []t.CommitType, code string) bool {
+	for _, commitType := range types {
+		if commitType.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// contains reports whether values holds value.
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
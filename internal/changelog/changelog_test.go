@@ -0,0 +1,281 @@
+package changelog
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/git"
+	t "github.com/GiulianoPoeta99/conventional_commits_cli/internal/types"
+)
+
+var testTypes = []t.CommitType{
+	{Code: "feat", Description: "Features"},
+	{Code: "fix", Description: "Bug Fixes"},
+	{Code: "chore", Description: "Chores"},
+}
+
+// testCommit returns a commit of message with a hash made of its index.
+func testCommit(i int, message string) git.Commit {
+	return git.Commit{Hash: strings.Repeat(string(rune('a'+i)), 40), Author: "Jane <jane@example.com>", Date: "2024-05-01T10:00:00Z", Message: message}
+}
+
+// sections returns the type codes of the sections with the headers of their entries.
+func sections(release Release) map[string][]string {
+	got := map[string][]string{}
+	for _, section := range release.Sections {
+		for _, entry := range section.Entries {
+			got[section.Type.Code] = append(got[section.Type.Code], entry.Commit.Description)
+		}
+	}
+	return got
+}
+
+// TestBuild checks how commits are grouped, counted and left out.
+func TestBuild(tt *testing.T) {
+	tests := []struct {
+		name     string
+		messages []string
+		hidden   []string
+		sections map[string][]string
+		order    []string
+		breaking []string
+		skipped  int
+		unknown  int
+	}{
+		{
+			name:     "grouped in catalogue order, newest first",
+			messages: []string{"fix: second fix", "feat: feature", "fix: first fix"},
+			sections: map[string][]string{"feat": {"feature"}, "fix": {"second fix", "first fix"}},
+			order:    []string{"feat", "fix"},
+		},
+		{
+			name:     "not conventional",
+			messages: []string{"feat: feature", "Update README", "WIP"},
+			sections: map[string][]string{"feat": {"feature"}},
+			order:    []string{"feat"},
+			skipped:  2,
+		},
+		{
+			name:     "ignored messages",
+			messages: []string{"Merge branch 'topic'", "fixup! feat: feature", "Revert \"feat: feature\"", "feat: feature"},
+			sections: map[string][]string{"feat": {"feature"}},
+			order:    []string{"feat"},
+		},
+		{
+			name:     "hidden type",
+			messages: []string{"chore: bump deps", "chore!: drop node 16", "feat: feature"},
+			hidden:   []string{"chore"},
+			sections: map[string][]string{"feat": {"feature"}},
+			order:    []string{"feat"},
+			breaking: []string{"drop node 16"},
+		},
+		{
+			name:     "unknown type",
+			messages: []string{"perf: faster", "perf!: new cache format", "fix: fix"},
+			sections: map[string][]string{"fix": {"fix"}},
+			order:    []string{"fix"},
+			breaking: []string{"new cache format"},
+			unknown:  2,
+		},
+		{
+			name:     "nothing",
+			sections: map[string][]string{},
+		},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			commits := []git.Commit{}
+			for i, message := range test.messages {
+				commits = append(commits, testCommit(i, message))
+			}
+			release := Build(commits, Options{Types: testTypes, Hidden: test.hidden})
+
+			if got := sections(release); !reflect.DeepEqual(got, test.sections) {
+				tt.Errorf("sections %q, want %q", got, test.sections)
+			}
+			order := []string{}
+			for _, section := range release.Sections {
+				order = append(order, section.Type.Code)
+			}
+			if len(order) > 0 || len(test.order) > 0 {
+				if !reflect.DeepEqual(order, test.order) {
+					tt.Errorf("section order %q, want %q", order, test.order)
+				}
+			}
+			breaking := []string{}
+			for _, entry := range release.Breaking {
+				breaking = append(breaking, entry.Commit.Description)
+			}
+			if len(breaking) > 0 || len(test.breaking) > 0 {
+				if !reflect.DeepEqual(breaking, test.breaking) {
+					tt.Errorf("breaking %q, want %q", breaking, test.breaking)
+				}
+			}
+			if release.Skipped != test.skipped || release.Unknown != test.unknown {
+				tt.Errorf("skipped %d and unknown %d, want %d and %d", release.Skipped, release.Unknown, test.skipped, test.unknown)
+			}
+		})
+	}
+}
+
+// TestBuildLinks checks the commit and issue links of the entries.
+func TestBuildLinks(tt *testing.T) {
+	c := testCommit(0, "feat: feature\n\nCloses: #12\nRefs: PROJ-7\nRefs: https://example.com/issues/3")
+	release := Build([]git.Commit{c}, Options{
+		Types:     testTypes,
+		IssueURL:  "https://tracker.example.com/{id}",
+		CommitURL: "https://example.com/commit/{hash}",
+	})
+
+	entry := release.Sections[0].Entries[0]
+	if entry.URL != "https://example.com/commit/"+c.Hash || entry.ShortHash != c.ShortHash() {
+		tt.Errorf("entry URL %q and short hash %q", entry.URL, entry.ShortHash)
+	}
+	want := []Link{
+		{Text: "#12", URL: "https://tracker.example.com/12"},
+		{Text: "PROJ-7", URL: "https://tracker.example.com/PROJ-7"},
+		{Text: "https://example.com/issues/3", URL: "https://example.com/issues/3"},
+	}
+	if !reflect.DeepEqual(entry.Refs, want) {
+		tt.Errorf("refs %+v, want %+v", entry.Refs, want)
+	}
+}
+
+// TestMarkdown checks the rendering of releases.
+func TestMarkdown(tt *testing.T) {
+	breaking := Entry{ShortHash: "aaaaaaa", Commit: t.CommitConfig{Type: testTypes[0], Scope: "api", Description: "page the lists", Breaking: true, BreakingReason: "lists return pages\nof 50 items"}}
+	fix := Entry{ShortHash: "bbbbbbb", URL: "https://example.com/b", Commit: t.CommitConfig{Type: testTypes[1], Description: "stop the crash"},
+		Refs: []Link{{Text: "#1", URL: "https://example.com/1"}, {Text: "PROJ-2"}}}
+
+	tests := []struct {
+		name    string
+		release Release
+		want    string
+	}{
+		{
+			name:    "empty",
+			release: Release{Version: Unreleased},
+			want:    "## Unreleased\n\nNo notable changes.\n",
+		},
+		{
+			name: "sections",
+			release: Release{
+				Version:  "v1.2.0",
+				Date:     "2024-05-01",
+				Breaking: []Entry{breaking},
+				Sections: []Section{{Type: testTypes[0], Entries: []Entry{breaking}}, {Type: testTypes[1], Entries: []Entry{fix}}},
+			},
+			want: "## v1.2.0 (2024-05-01)\n\n" +
+				"### ⚠ BREAKING CHANGES\n\n" +
+				"- **api:** page the lists (aaaaaaa)\n  lists return pages\n  of 50 items\n\n" +
+				"### Features (feat)\n\n" +
+				"- **api:** page the lists (aaaaaaa)\n\n" +
+				"### Bug Fixes (fix)\n\n" +
+				"- stop the crash ([bbbbbbb](https://example.com/b)), refs [#1](https://example.com/1), PROJ-2\n",
+		},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			if got := Markdown(test.release); got != test.want {
+				tt.Errorf("Markdown():\n%s\nwant:\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+// TestJSON checks that the JSON rendering reads back into the same release.
+func TestJSON(tt *testing.T) {
+	release := Build([]git.Commit{testCommit(0, "feat(api)!: page the lists\n\nRefs: #3"), testCommit(1, "oops")}, Options{Types: testTypes})
+	content, err := JSON(release)
+	if err != nil {
+		tt.Fatal(err)
+	}
+	if !strings.HasSuffix(content, "}\n") || !strings.Contains(content, "\n  \"skipped\": 1") {
+		tt.Errorf("JSON() is not indented or lacks the counts:\n%s", content)
+	}
+
+	var back Release
+	if err := json.Unmarshal([]byte(content), &back); err != nil {
+		tt.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, release) {
+		tt.Errorf("JSON() read back as %+v, want %+v", back, release)
+	}
+}
+
+// TestPrepend checks where sections are inserted in the changelog file.
+func TestPrepend(tt *testing.T) {
+	section := "## v1.1.0\n\n- new\n"
+	tests := []struct {
+		name     string
+		existing *string
+		version  string
+		want     string
+		err      error
+	}{
+		{
+			name:    "missing file",
+			version: "v1.1.0",
+			want:    section,
+		},
+		{
+			name:     "below the title",
+			existing: ptr("# Changelog\n\nAll notable changes.\n\n## v1.0.0\n\n- old\n"),
+			version:  "v1.1.0",
+			want:     "# Changelog\n\nAll notable changes.\n\n## v1.1.0\n\n- new\n\n## v1.0.0\n\n- old\n",
+		},
+		{
+			name:     "without title",
+			existing: ptr("## v1.0.0\n\n- old\n"),
+			version:  "v1.1.0",
+			want:     "## v1.1.0\n\n- new\n\n## v1.0.0\n\n- old\n",
+		},
+		{
+			name:     "replacing unreleased",
+			existing: ptr("# Changelog\n\n## Unreleased\n\n- pending\n\n## v1.0.0 (2024-01-01)\n\n- old\n"),
+			version:  "v1.1.0",
+			want:     "# Changelog\n\n## v1.1.0\n\n- new\n\n## v1.0.0 (2024-01-01)\n\n- old\n",
+		},
+		{
+			name:     "already released",
+			existing: ptr("## v1.1.0 (2024-02-01)\n\n- new\n"),
+			version:  "v1.1.0",
+			want:     "## v1.1.0 (2024-02-01)\n\n- new\n",
+			err:      ErrAlreadyReleased,
+		},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			path := filepath.Join(tt.TempDir(), "CHANGELOG.md")
+			if test.existing != nil {
+				if err := os.WriteFile(path, []byte(*test.existing), 0o644); err != nil {
+					tt.Fatal(err)
+				}
+			}
+
+			if err := Prepend(path, test.version, section); !errors.Is(err, test.err) {
+				tt.Fatalf("Prepend() = %v, want %v", err, test.err)
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				tt.Fatal(err)
+			}
+			if string(content) != test.want {
+				tt.Errorf("changelog:\n%s\nwant:\n%s", content, test.want)
+			}
+		})
+	}
+}
+
+// ptr returns a pointer to s.
+func ptr(s string) *string {
+	return &s
+}
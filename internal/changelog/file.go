@@ -0,0 +1,73 @@
+package changelog
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// Unreleased is the version used for changes that are not tagged yet.
+const Unreleased = "Unreleased"
+
+// ErrAlreadyReleased is returned when the file already holds the section of a release.
+var ErrAlreadyReleased = errors.New("the changelog already contains this release")
+
+// Prepend inserts the Markdown section of a release at the top of the changelog at path,
+// below its title if it has one. An existing "Unreleased" section is replaced, as its
+// changes now belong to the new section, while an existing section for a released
+// version is kept and ErrAlreadyReleased is returned.
+func Prepend(path, version, section string) error {
+	content, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	lines := strings.Split(string(content), "\n")
+
+	if start, _ := findSection(lines, version); start >= 0 && version != Unreleased {
+		return ErrAlreadyReleased
+	}
+
+	// Remove the pending changes, which the new section supersedes.
+	if start, end := findSection(lines, Unreleased); start >= 0 {
+		lines = append(lines[:start], lines[end:]...)
+	}
+
+	// Keep the document title and introduction above the releases.
+	insert := 0
+	for insert < len(lines) && !strings.HasPrefix(lines[insert], "## ") {
+		insert++
+	}
+	if insert == len(lines) && len(lines) > 0 && !strings.HasPrefix(lines[0], "# ") {
+		insert = 0
+	}
+
+	head := strings.TrimRight(strings.Join(lines[:insert], "\n"), "\n")
+	tail := strings.TrimLeft(strings.Join(lines[insert:], "\n"), "\n")
+
+	output := ""
+	if head != "" {
+		output = head + "\n\n"
+	}
+	output += strings.TrimRight(section, "\n") + "\n"
+	if tail != "" {
+		output += "\n" + tail
+	}
+
+	return os.WriteFile(path, []byte(output), 0o644)
+}
+
+// findSection returns the line range [start, end) of the "## version" section, or -1.
+func findSection(lines []string, version string) (int, int) {
+	for start, line := range lines {
+		if line != "## "+version && !strings.HasPrefix(line, "## "+version+" ") {
+			continue
+		}
+
+		end := start + 1
+		for end < len(lines) && !strings.HasPrefix(lines[end], "## ") {
+			end++
+		}
+		return start, end
+	}
+	return -1, -1
+}
@@ -0,0 +1,106 @@
+package changelog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// Markdown renders the release as a "## version (date)" section.
+func Markdown(release Release) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## %s", release.Version)
+	if release.Date != "" {
+		fmt.Fprintf(&b, " (%s)", release.Date)
+	}
+	b.WriteString("\n")
+
+	if len(release.Breaking) > 0 {
+		b.WriteString("\n### ⚠ BREAKING CHANGES\n\n")
+		for _, entry := range release.Breaking {
+			line := entryLine(entry)
+			if reason := entry.Commit.BreakingReason; reason != "" {
+				line += "\n  " + strings.ReplaceAll(reason, "\n", "\n  ")
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+
+	for _, section := range release.Sections {
+		fmt.Fprintf(&b, "\n### %s (%s)\n\n", section.Type.Description, section.Type.Code)
+		for _, entry := range section.Entries {
+			b.WriteString(entryLine(entry) + "\n")
+		}
+	}
+
+	if len(release.Breaking) == 0 && len(release.Sections) == 0 {
+		b.WriteString("\nNo notable changes.\n")
+	}
+
+	return b.String()
+}
+
+// entryLine renders one entry as a Markdown list item.
+func entryLine(entry Entry) string {
+	line := "- "
+	if entry.Commit.Scope != "" {
+		line += "**" + entry.Commit.Scope + ":** "
+	}
+	line += entry.Commit.Description
+
+	hash := entry.ShortHash
+	if entry.URL != "" {
+		hash = "[" + hash + "](" + entry.URL + ")"
+	}
+	line += " (" + hash + ")"
+
+	if len(entry.Refs) > 0 {
+		refs := []string{}
+		for _, ref := range entry.Refs {
+			if ref.URL != "" {
+				refs = append(refs, "["+ref.Text+"]("+ref.URL+")")
+			} else {
+				refs = append(refs, ref.Text)
+			}
+		}
+		line += ", refs " + strings.Join(refs, ", ")
+	}
+
+	return line
+}
+
+// JSON renders the release as indented JSON.
+func JSON(release Release) (string, error) {
+	content, err := json.MarshalIndent(release, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(content) + "\n", nil
+}
+
+// Template renders the release with the Go text/template stored at path.
+// The template receives the Release value as its data.
+func Template(release Release, path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	tmpl, err := template.New(path).Funcs(template.FuncMap{
+		"join":  strings.Join,
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+	}).Parse(string(content))
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, release); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,50 @@
+// Package clipboard copies text to the system clipboard through the usual command-line
+// tools.
+package clipboard
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// tool is a command copying its standard input to the clipboard.
+type tool struct {
+	args []string
+	// env, when set, is the environment variable naming the display the tool needs.
+	env string
+}
+
+// tools are tried in order: macOS, Wayland, X11 then Windows (including WSL).
+var tools = []tool{
+	{args: []string{"pbcopy"}},
+	{args: []string{"wl-copy"}, env: "WAYLAND_DISPLAY"},
+	{args: []string{"xclip", "-selection", "clipboard"}, env: "DISPLAY"},
+	{args: []string{"xsel", "--clipboard", "--input"}, env: "DISPLAY"},
+	{args: []string{"clip.exe"}},
+}
+
+// Find returns the command line of the first clipboard tool usable here, or nil when
+// there is none.
+func Find() []string {
+	for _, tool := range tools {
+		if tool.env != "" && os.Getenv(tool.env) == "" {
+			continue
+		}
+		if _, err := exec.LookPath(tool.args[0]); err == nil {
+			return tool.args
+		}
+	}
+	return nil
+}
+
+// Copy writes text to the standard input of the clipboard tool run by args.
+func Copy(args []string, text string) error {
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", args[0], err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -4,17 +4,23 @@ package internal
 import (
 	"errors"
 	"fmt"
-	"os"
-	"os/exec"
+	"io"
 	"strings"
+	"unicode/utf8"
 
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/git"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/issue"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/trailer"
 	t "github.com/GiulianoPoeta99/conventional_commits_cli/internal/types"
-	ui "github.com/GiulianoPoeta99/conventional_commits_cli/internal/ui"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/wrap"
 )
 
+// DefaultBreakingReason is the BREAKING CHANGE footer used when no reason is given.
+const DefaultBreakingReason = "This commit introduces changes incompatible with previous versions"
+
 // FormatCommitMessage formats the commit message according to the provided configuration.
 // It constructs the message by combining type, scope, emoji, description, body, breaking changes,
-// reviewers, and referenced issues.
+// the people credited, referenced issues, and any other trailers.
 func FormatCommitMessage(config t.CommitConfig) string {
 	message := config.Type.Code
 
@@ -52,77 +58,201 @@ func FormatCommitMessage(config t.CommitConfig) string {
 		if config.BreakingReason != "" {
 			message += config.BreakingReason
 		} else {
-			message += "This commit introduces changes incompatible with previous versions"
+			message += DefaultBreakingReason
 		}
 	}
 
-	// Append reviewers information.
-	if len(config.Reviewers) > 0 {
-		if !strings.HasSuffix(message, "\n\n") {
-			message += "\n\n"
-		}
+	// Append the people credited, the referenced issues and the other trailers as a
+	// single trailer block, which git interpret-trailers reads back unchanged.
+	if footers := Footers(config); len(footers) > 0 {
+		message += "\n\n"
 
-		for _, reviewer := range config.Reviewers {
-			message += "Reviewed-by: " + reviewer + "\n"
+		for _, footer := range footers {
+			message += footer.Key + ": " + trailer.Fold(footer.Value) + "\n"
 		}
 		message = strings.TrimSuffix(message, "\n")
 	}
 
-	// Append referenced issues.
-	if len(config.ReferenceIssues) > 0 {
-		if !strings.HasSuffix(message, "\n\n") && !strings.HasSuffix(message, "\n") {
-			message += "\n\n"
-		} else if strings.HasSuffix(message, "\n") {
-			message += "\n"
-		} else {
-			message += "\n\n"
+	return message
+}
+
+// WrapCommitConfig returns a copy of config whose body and footer values are wrapped
+// at width columns, so that FormatCommitMessage produces lines readable in git log.
+// A zero width leaves config unchanged.
+func WrapCommitConfig(config t.CommitConfig, width int) t.CommitConfig {
+	if width <= 0 {
+		return config
+	}
+
+	config.Body = wrap.Body(config.Body, width)
+
+	// The default reason is spelled out so that it is wrapped as well.
+	if config.Breaking {
+		reason := config.BreakingReason
+		if reason == "" {
+			reason = DefaultBreakingReason
 		}
+		config.BreakingReason = wrap.Footer("BREAKING CHANGE", reason, width)
+	}
 
-		for _, issue := range config.ReferenceIssues {
-			message += "Refs: " + issue + "\n"
+	for _, people := range People(&config) {
+		*people.Values = wrapFooters(people.Key, *people.Values, width)
+	}
+
+	refs := make([]t.IssueReference, len(config.ReferenceIssues))
+	for i, ref := range config.ReferenceIssues {
+		relation := ref.Relation
+		if relation == "" {
+			relation = issue.DefaultRelation
 		}
-		message = strings.TrimSuffix(message, "\n")
+		refs[i] = t.IssueReference{Relation: relation, Issue: wrap.Footer(relation, ref.Issue, width)}
 	}
+	config.ReferenceIssues = refs
 
-	return message
+	trailers := make([]t.Trailer, len(config.Trailers))
+	for i, footer := range config.Trailers {
+		trailers[i] = t.Trailer{Key: footer.Key, Value: wrap.Footer(footer.Key, footer.Value, width)}
+	}
+	config.Trailers = trailers
+
+	return config
 }
 
-// executeCommit executes the commit using git commands.
-// First, it checks if there are staged changes and then commits with the provided message.
-func executeCommit(message string) error {
-	// Check for staged changes.
-	cmd := exec.Command("git", "diff", "--staged", "--quiet")
-	err := cmd.Run()
+// CoAuthorKey is the trailer key crediting co-authors, who are asked for by their own
+// step rather than with the other roles.
+const CoAuthorKey = "Co-authored-by"
 
-	if err != nil {
-		// If there are staged changes, perform the commit.
-		cmd = exec.Command("git", "commit", "-m", message)
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		return cmd.Run()
-	} else {
-		return errors.New("no staged changes to commit. Use 'git add' first")
+// Role is a trailer crediting people for their part in a change.
+type Role struct {
+	// Key is the trailer key, e.g. "Reviewed-by".
+	Key string
+	// Flag is the name of the repeatable command-line flag adding people to the role.
+	Flag string
+	// Description tells the user what the person did.
+	Description string
+	// values returns the list of config holding the people credited in the role.
+	values func(config *t.CommitConfig) *[]string
+}
+
+// Roles lists the trailers crediting people, in rendering order.
+var Roles = []Role{
+	{Key: "Reviewed-by", Flag: "reviewer", Description: "reviewed the change", values: func(c *t.CommitConfig) *[]string { return &c.Reviewers }},
+	{Key: "Acked-by", Flag: "acked-by", Description: "approves the change", values: func(c *t.CommitConfig) *[]string { return &c.AckedBy }},
+	{Key: "Tested-by", Flag: "tested-by", Description: "tested the change", values: func(c *t.CommitConfig) *[]string { return &c.TestedBy }},
+	{Key: "Reported-by", Flag: "reported-by", Description: "reported the problem", values: func(c *t.CommitConfig) *[]string { return &c.ReportedBy }},
+	{Key: CoAuthorKey, Flag: "co-author", Description: "co-wrote the change", values: func(c *t.CommitConfig) *[]string { return &c.CoAuthors }},
+}
+
+// PeopleList is the list of identities of config credited under a trailer key.
+type PeopleList struct {
+	Key    string
+	Values *[]string
+}
+
+// People returns the lists of config crediting people, in the order of Roles.
+func People(config *t.CommitConfig) []PeopleList {
+	lists := make([]PeopleList, len(Roles))
+	for i, role := range Roles {
+		lists[i] = PeopleList{Key: role.Key, Values: role.values(config)}
+	}
+	return lists
+}
+
+// PeopleFooters returns the footers crediting people, in rendering order.
+func PeopleFooters(config *t.CommitConfig) []t.Trailer {
+	footers := []t.Trailer{}
+	for _, people := range People(config) {
+		for _, value := range *people.Values {
+			footers = append(footers, t.Trailer{Key: people.Key, Value: value})
+		}
 	}
+	return footers
 }
 
-// ConfirmAndCommit prints the commit message for confirmation and then executes the commit if confirmed.
-// It returns an error if the commit is cancelled or if an error occurs during the commit process.
-func ConfirmAndCommit(message string) error {
-	fmt.Println("\n============= Commit message =============")
-	fmt.Println()
-	fmt.Println(message)
-	fmt.Println()
-	fmt.Println("==========================================")
+// Footers returns the trailers of config in rendering order: the people credited, the
+// referenced issues under their relation, then the other trailers. Empty values are
+// left out.
+func Footers(config t.CommitConfig) []t.Trailer {
+	footers := []t.Trailer{}
+	add := func(key, value string) {
+		if strings.TrimSpace(value) != "" {
+			footers = append(footers, t.Trailer{Key: key, Value: value})
+		}
+	}
+
+	for _, footer := range PeopleFooters(&config) {
+		add(footer.Key, footer.Value)
+	}
+	for _, ref := range config.ReferenceIssues {
+		relation := ref.Relation
+		if relation == "" {
+			relation = issue.DefaultRelation
+		}
+		add(relation, ref.Issue)
+	}
+	for _, footer := range config.Trailers {
+		add(footer.Key, footer.Value)
+	}
+	return footers
+}
+
+// wrapFooters wraps the values of footers sharing the same key into a new slice.
+func wrapFooters(key string, values []string, width int) []string {
+	if values == nil {
+		return nil
+	}
 
-	confirm, err := ui.ConfirmSelect("Confirm commit?")
+	wrapped := make([]string, len(values))
+	for i, value := range values {
+		wrapped[i] = wrap.Footer(key, value, width)
+	}
+	return wrapped
+}
+
+// executeCommit executes the commit through the given repository.
+// First, it checks if there are staged changes and then commits with the provided message.
+func executeCommit(g git.Git, message string, opts git.CommitOptions) error {
+	// Check for staged changes; amending may only reword HEAD.
+	staged, err := g.HasStagedChanges()
 	if err != nil {
-		fmt.Printf("Error in the confirmation of the commit: %v\n", err)
-		os.Exit(1)
+		return err
+	}
+
+	if !staged && !opts.Amend && !opts.AllowEmpty {
+		return errors.New("no staged changes to commit. Use 'git add' first")
 	}
 
-	if confirm {
-		return executeCommit(message)
+	// If there are staged changes, perform the commit.
+	return g.Commit(message, opts)
+}
+
+// Commit executes the commit without asking for confirmation.
+// It is used when the message was fully specified on the command line.
+func Commit(g git.Git, message string, opts git.CommitOptions) error {
+	return executeCommit(g, message, opts)
+}
+
+// Preview prints the commit message to out framed for review, followed by the warning
+// of HeaderWarning, if any.
+func Preview(out io.Writer, message string, headerMaxLength int) {
+	fmt.Fprintln(out, "\n============= Commit message =============")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, message)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "==========================================")
+
+	if warning := HeaderWarning(message, headerMaxLength); warning != "" {
+		fmt.Fprintf(out, "⚠️  %s\n", warning)
 	}
+}
 
-	return errors.New("commit canceled by user")
+// HeaderWarning returns a warning when the header of message is longer than
+// headerMaxLength characters, as it will be truncated by git log and code hosting
+// tools, and an empty string otherwise. Zero disables the warning.
+func HeaderWarning(message string, headerMaxLength int) string {
+	header, _, _ := strings.Cut(message, "\n")
+	if length := utf8.RuneCountInString(header); headerMaxLength > 0 && length > headerMaxLength {
+		return fmt.Sprintf("The header is %d characters long, the maximum is %d", length, headerMaxLength)
+	}
+	return ""
 }
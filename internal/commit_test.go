@@ -0,0 +1,106 @@
+package internal
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/trailer"
+	t "github.com/GiulianoPoeta99/conventional_commits_cli/internal/types"
+)
+
+// TestHeaderWarning checks that only headers longer than the maximum are warned
+// about, counting characters rather than bytes.
+func TestHeaderWarning(tt *testing.T) {
+	tests := []struct {
+		message string
+		max     int
+		want    string
+	}{
+		{message: "feat: short\n\n" + strings.Repeat("long body ", 20), max: 72},
+		{message: "feat: " + strings.Repeat("é", 66), max: 72},
+		{message: "feat: " + strings.Repeat("x", 67), max: 72, want: "The header is 73 characters long, the maximum is 72"},
+		{message: "feat: " + strings.Repeat("x", 67), max: 0},
+	}
+
+	for _, test := range tests {
+		if got := HeaderWarning(test.message, test.max); got != test.want {
+			tt.Errorf("HeaderWarning(%q, %d) = %q, want %q", test.message, test.max, got, test.want)
+		}
+	}
+}
+
+// TestFormatCommitMessageInterpretTrailers checks that git interpret-trailers --parse
+// reads back exactly the footers of the formatted message, with folded values unfolded
+// and the BREAKING CHANGE paragraph left out of the trailer block.
+func TestFormatCommitMessageInterpretTrailers(tt *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		tt.Skip("git is not installed")
+	}
+	// trailer.* settings of the user would change how git reads the trailers.
+	tt.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	tt.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	long := strings.Repeat("a value long enough to be wrapped ", 4)
+	tests := []struct {
+		name   string
+		config t.CommitConfig
+	}{
+		{
+			name:   "no footers",
+			config: t.CommitConfig{Body: "Just a body."},
+		},
+		{
+			name: "every kind of footer",
+			config: t.CommitConfig{
+				Body:            "Explain the change.",
+				Reviewers:       []string{"Ann <ann@example.com>"},
+				CoAuthors:       []string{"Bob <bob@example.com>"},
+				ReferenceIssues: []t.IssueReference{{Relation: "Closes", Issue: "#12"}, {Issue: "PROJ-7"}},
+				Trailers:        []t.Trailer{{Key: "Signed-off-by", Value: "Jane Doe <jane@example.com>"}, {Key: "Change-Id", Value: "I0123"}},
+			},
+		},
+		{
+			name: "breaking change paragraph",
+			config: t.CommitConfig{
+				Breaking:        true,
+				BreakingReason:  "the list endpoints return pages",
+				ReferenceIssues: []t.IssueReference{{Relation: "Refs", Issue: "#3"}},
+			},
+		},
+		{
+			name: "folded values",
+			config: t.CommitConfig{
+				Body:           long,
+				Breaking:       true,
+				BreakingReason: long,
+				Trailers:       []t.Trailer{{Key: "X-Note", Value: long}, {Key: "X-Lines", Value: "first line\nsecond line"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			config := test.config
+			config.Type, config.Description = testTypes[0], "add pagination"
+			config = WrapCommitConfig(config, 50)
+			message := FormatCommitMessage(config)
+
+			cmd := exec.Command("git", "interpret-trailers", "--parse")
+			cmd.Stdin = strings.NewReader(message + "\n")
+			output, err := cmd.Output()
+			if err != nil {
+				tt.Fatal(err)
+			}
+
+			want := ""
+			for _, footer := range Footers(config) {
+				want += footer.Key + ": " + strings.ReplaceAll(trailer.Fold(footer.Value), "\n ", " ") + "\n"
+			}
+			if string(output) != want {
+				tt.Errorf("git interpret-trailers --parse of\n%s\ngave:\n%s\nwant:\n%s", message, output, want)
+			}
+		})
+	}
+}
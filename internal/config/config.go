@@ -0,0 +1,286 @@
+// Package config loads the user and project configuration files and merges them
+// over the built-in commit types and emojis.
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	d "github.com/GiulianoPoeta99/conventional_commits_cli/internal/data"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/infer"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/issue"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/lint"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/trailer"
+	t "github.com/GiulianoPoeta99/conventional_commits_cli/internal/types"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/wrap"
+)
+
+// AppName is the directory name used for the user-level configuration.
+const AppName = "conventional_commits_cli"
+
+// ProjectFileNames lists the accepted names of the repository configuration file,
+// in order of preference when several of them exist in the same directory.
+var ProjectFileNames = []string{".commitrc.yaml", ".commitrc.yml", ".commitrc.json"}
+
+// UserFileNames lists the accepted names of the user configuration file.
+var UserFileNames = []string{"config.yaml", "config.yml", "config.json"}
+
+// Config holds the effective configuration after merging every source.
+type Config struct {
+	// Types is the catalogue of commit types offered by the wizard.
+	Types []t.CommitType
+	// Emojis is the catalogue of emojis offered by the wizard.
+	Emojis []t.Emoji
+	// TypeEmojis maps a commit type code to its recommended emoji codes.
+	TypeEmojis map[string][]string
+	// Changelog configures the changelog generator.
+	Changelog Changelog
+	// Bump configures the version bump calculation.
+	Bump Bump
+	// Message configures how the wizard writes commit messages.
+	Message Message
+	// Scopes configures the scope suggestions.
+	Scopes Scopes
+	// Issues configures the issue references.
+	Issues Issues
+	// Trailers declares the required and optional trailers of the project, in order.
+	Trailers []trailer.Rule
+	// Team configures the people offered as reviewers and co-authors.
+	Team Team
+	// TypeRules suggest a commit type from the staged changes, in order of precedence.
+	TypeRules []infer.TypeRule
+	// Files lists the configuration files that were applied, in order.
+	Files []string
+}
+
+// Changelog configures the changelog generator.
+type Changelog struct {
+	// IssueURL links issue references; "{id}" is replaced by the issue number or key.
+	IssueURL string
+	// CommitURL links commits; "{hash}" is replaced by the full commit hash.
+	CommitURL string
+	// Hidden lists the commit type codes left out of the changelog.
+	Hidden []string
+}
+
+// Bump configures the version bump calculation.
+type Bump struct {
+	// TagPrefix is the part of the release tags before the version, e.g. "api/".
+	TagPrefix string
+	// Levels maps a commit type code to the increment it triggers:
+	// "none", "patch", "minor" or "major". Breaking changes always trigger "major".
+	Levels map[string]string
+}
+
+// The ways of entering the commit body.
+const (
+	// BodyInputLine asks for the body on a single line.
+	BodyInputLine = "line"
+	// BodyInputMultiline asks for the body line by line in the terminal.
+	BodyInputMultiline = "multiline"
+	// BodyInputEditor writes the body in the user's editor.
+	BodyInputEditor = "editor"
+)
+
+// BodyInputs lists the accepted values of Message.BodyInput.
+var BodyInputs = []string{BodyInputLine, BodyInputMultiline, BodyInputEditor}
+
+// Message configures how the wizard writes commit messages.
+type Message struct {
+	// BodyInput is how the body is entered: "line", "multiline" or "editor".
+	// The editor falls back to multiline input when none is configured.
+	BodyInput string
+	// WrapWidth is the column at which the body and footers are wrapped; zero disables wrapping.
+	WrapWidth int
+	// HeaderMaxLength is the longest header accepted without a warning; zero disables the check.
+	HeaderMaxLength int
+	// Signoff adds a Signed-off-by trailer with the user's identity, as --signoff does.
+	Signoff bool
+}
+
+// Issues configures the issue references.
+type Issues struct {
+	// Syntaxes lists the accepted reference syntaxes; see issue.Names.
+	Syntaxes []string
+	// FromBranch offers the references found in the branch name.
+	FromBranch bool
+	// FromBranchUnattended adds the references found in the branch name when no prompt
+	// can be shown. It is off by default, as a guess nobody confirmed could be wrong.
+	FromBranchUnattended bool
+	// BranchPatterns extract references from the branch name.
+	BranchPatterns []issue.BranchPattern
+	// Relations lists the footer keys offered for references, in rendering order.
+	Relations []string
+}
+
+// Team configures the people offered as reviewers and co-authors.
+type Team struct {
+	// Roster is the path of a file listing the team members, one "Name <email>" per line.
+	Roster string
+}
+
+// Scopes configures the scope suggestions.
+type Scopes struct {
+	// Rules map staged paths to scopes, before the scopes derived from the layout.
+	Rules []infer.ScopeRule
+}
+
+// Default returns the built-in configuration.
+func Default() Config {
+	return Config{
+		Types:      d.GetCommitTypes(),
+		Emojis:     d.GetEmojis(),
+		TypeEmojis: d.GetTypeEmojis(),
+		Bump: Bump{
+			Levels: map[string]string{
+				"feat": "minor",
+				"fix":  "patch",
+				"perf": "patch",
+			},
+		},
+		TypeRules: infer.DefaultTypeRules(),
+		Issues: Issues{
+			Syntaxes:       issue.Names(),
+			FromBranch:     true,
+			BranchPatterns: issue.DefaultBranchPatterns(),
+			Relations:      issue.Relations,
+		},
+		Message: Message{
+			BodyInput:       BodyInputLine,
+			WrapWidth:       wrap.DefaultWidth,
+			HeaderMaxLength: lint.DefaultHeaderMaxLength,
+		},
+	}
+}
+
+// Load builds the configuration for the current working directory.
+func Load() (Config, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return Config{}, err
+	}
+	return LoadFrom(dir)
+}
+
+// LoadFrom builds the configuration for the given directory.
+// The user file is applied first and the repository files after it, starting at
+// the git root and walking down to dir, so the nearest file has the last word.
+func LoadFrom(dir string) (Config, error) {
+	config := Default()
+
+	paths := []string{}
+	if userDir, err := UserDir(); err == nil {
+		if path := findFile(userDir, UserFileNames); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	paths = append(paths, projectFiles(dir)...)
+
+	for _, path := range paths {
+		file, err := readFile(path)
+		if err != nil {
+			return Config{}, err
+		}
+		if err := config.apply(path, file); err != nil {
+			return Config{}, err
+		}
+		config.Files = append(config.Files, path)
+	}
+
+	return config, nil
+}
+
+// UserDir returns the directory holding the user configuration.
+// It honours $XDG_CONFIG_HOME and falls back to ~/.config.
+func UserDir() (string, error) {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, AppName), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", AppName), nil
+}
+
+// FindGitRoot walks up from dir and returns the first directory containing a
+// .git entry, or an empty string when dir is not inside a repository.
+func FindGitRoot(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// projectFiles returns the repository configuration files that apply to dir,
+// ordered from the git root down to dir.
+func projectFiles(dir string) []string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+
+	root := FindGitRoot(dir)
+	if root == "" {
+		root = dir
+	}
+
+	files := []string{}
+	for {
+		if path := findFile(dir, ProjectFileNames); path != "" {
+			files = append([]string{path}, files...)
+		}
+
+		parent := filepath.Dir(dir)
+		if dir == root || parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return files
+}
+
+// findFile returns the first of names that exists in dir.
+func findFile(dir string, names []string) string {
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// FindType returns the commit type with the given code.
+func (c Config) FindType(code string) (t.CommitType, bool) {
+	for _, commitType := range c.Types {
+		if commitType.Code == code {
+			return commitType, true
+		}
+	}
+	return t.CommitType{}, false
+}
+
+// FindEmoji returns the emoji with the given code.
+func (c Config) FindEmoji(code string) (t.Emoji, bool) {
+	for _, emoji := range c.Emojis {
+		if emoji.Code == code {
+			return emoji, true
+		}
+	}
+	return t.Emoji{}, false
+}
@@ -0,0 +1,223 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/infer"
+)
+
+// testDirs creates a repository with a nested package directory and an empty user
+// configuration directory, and returns the root, the nested directory and the user directory.
+func testDirs(tt *testing.T) (string, string, string) {
+	tt.Helper()
+	base := tt.TempDir()
+	root := filepath.Join(base, "repo")
+	nested := filepath.Join(root, "packages", "api")
+	user := filepath.Join(base, "xdg", AppName)
+	for _, dir := range []string{filepath.Join(root, ".git"), nested, user} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			tt.Fatal(err)
+		}
+	}
+	tt.Setenv("XDG_CONFIG_HOME", filepath.Join(base, "xdg"))
+	return root, nested, user
+}
+
+// writeConfig writes a configuration file and returns its path.
+func writeConfig(tt *testing.T, dir, name, content string) string {
+	tt.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		tt.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFromMergeOrder(tt *testing.T) {
+	root, nested, user := testDirs(tt)
+	userFile := writeConfig(tt, user, "config.yaml", `
+types:
+  - code: feat
+    description: user feature
+  - code: wip
+    description: work in progress
+message:
+  wrapWidth: 60
+  signoff: true
+scopes:
+  rules:
+    - glob: "**"
+      scope: user
+`)
+	rootFile := writeConfig(tt, root, ".commitrc.yaml", `
+types:
+  - code: feat
+    description: root feature
+message:
+  wrapWidth: 80
+scopes:
+  rules:
+    - glob: "packages/**"
+      scope: root
+`)
+	nestedFile := writeConfig(tt, nested, ".commitrc.json", `{
+  "types": [{"code": "feat", "description": "api feature"}],
+  "bump": {"levels": {"wip": "patch"}},
+  "scopes": {"rules": [{"glob": "packages/api/**", "scope": "api"}]}
+}`)
+
+	config, err := LoadFrom(nested)
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	if want := []string{userFile, rootFile, nestedFile}; !slices.Equal(config.Files, want) {
+		tt.Errorf("Files = %q, want %q", config.Files, want)
+	}
+	if feat, _ := config.FindType("feat"); feat.Description != "api feature" {
+		tt.Errorf("feat description = %q, want the nearest file's", feat.Description)
+	}
+	if _, ok := config.FindType("wip"); !ok {
+		tt.Error("the type added by the user file is missing")
+	}
+	if config.Message.WrapWidth != 80 {
+		tt.Errorf("WrapWidth = %d, want the root file's 80", config.Message.WrapWidth)
+	}
+	if !config.Message.Signoff {
+		tt.Error("Signoff set by the user file was lost")
+	}
+	if config.Bump.Levels["wip"] != "patch" || config.Bump.Levels["feat"] != "minor" {
+		tt.Errorf("Levels = %v, want wip added to the defaults", config.Bump.Levels)
+	}
+	wantRules := []infer.ScopeRule{
+		{Glob: "packages/api/**", Scope: "api"},
+		{Glob: "packages/**", Scope: "root"},
+		{Glob: "**", Scope: "user"},
+	}
+	if !slices.Equal(config.Scopes.Rules, wantRules) {
+		tt.Errorf("Scopes.Rules = %v, want %v", config.Scopes.Rules, wantRules)
+	}
+}
+
+func TestLoadFromStopsAtGitRoot(tt *testing.T) {
+	root, nested, _ := testDirs(tt)
+	writeConfig(tt, filepath.Dir(root), ".commitrc.yaml", "message:\n  wrapWidth: 10\n")
+
+	config, err := LoadFrom(nested)
+	if err != nil {
+		tt.Fatal(err)
+	}
+	if len(config.Files) != 0 {
+		tt.Errorf("Files = %q, want none above the git root", config.Files)
+	}
+	if config.Message.WrapWidth != Default().Message.WrapWidth {
+		tt.Errorf("WrapWidth = %d, want the default", config.Message.WrapWidth)
+	}
+}
+
+func TestLoadFromErrors(tt *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    Error
+	}{
+		{
+			name:    "yaml syntax",
+			file:    ".commitrc.yaml",
+			content: "types:\n  - code: feat\n    description: [unclosed\n",
+			want:    Error{Line: 2, Message: "did not find expected ',' or ']'"},
+		},
+		{
+			name:    "yaml unknown key",
+			file:    ".commitrc.yaml",
+			content: "message:\n  wrapWidht: 60\n",
+			want:    Error{Line: 2, Key: "message.wrapWidht", Message: `unknown key "wrapWidht"`},
+		},
+		{
+			name:    "yaml wrong type",
+			file:    ".commitrc.yaml",
+			content: "types:\n  - code: feat\n  - code: docs\n    description: [a, b]\n",
+			want:    Error{Line: 4, Key: "types[1].description", Message: "cannot unmarshal !!seq into string"},
+		},
+		{
+			name:    "json syntax",
+			file:    ".commitrc.json",
+			content: "{\n  \"types\": [\n}\n",
+			want:    Error{Line: 3, Message: "invalid character '}' looking for beginning of value"},
+		},
+		{
+			name:    "json wrong type",
+			file:    ".commitrc.json",
+			content: "{\n  \"message\": {\"wrapWidth\": \"wide\"}\n}\n",
+			want:    Error{Line: 2, Key: "message.wrapWidth", Message: "cannot use string as int"},
+		},
+		{
+			name:    "invalid type code",
+			file:    ".commitrc.yaml",
+			content: "types:\n  - code: feat\n  - code: Docs\n    description: docs\n",
+			want:    Error{Key: "types[1].code", Message: `invalid commit type "Docs" (use lowercase letters, digits and dashes)`},
+		},
+		{
+			name:    "missing description",
+			file:    ".commitrc.yaml",
+			content: "types:\n  - code: wip\n",
+			want:    Error{Key: "types[0].description", Message: "description is required for new commit types"},
+		},
+		{
+			name:    "unknown emoji for type",
+			file:    ".commitrc.yaml",
+			content: "typeEmojis:\n  feat: [sparkles, nope]\n",
+			want:    Error{Key: "typeEmojis.feat[1]", Message: `unknown emoji "nope"`},
+		},
+		{
+			name:    "unknown hidden type",
+			file:    ".commitrc.json",
+			content: `{"changelog": {"hidden": ["docs", "nope"]}}`,
+			want:    Error{Key: "changelog.hidden[1]", Message: `unknown commit type "nope"`},
+		},
+		{
+			name:    "invalid bump level",
+			file:    ".commitrc.yaml",
+			content: "bump:\n  levels:\n    docs: huge\n",
+			want:    Error{Key: "bump.levels.docs"},
+		},
+		{
+			name:    "negative wrap width",
+			file:    ".commitrc.yaml",
+			content: "message:\n  wrapWidth: -1\n",
+			want:    Error{Key: "message.wrapWidth", Message: "width cannot be negative (use 0 to disable wrapping)"},
+		},
+		{
+			name:    "missing scope",
+			file:    ".commitrc.yaml",
+			content: "scopes:\n  rules:\n    - glob: \"src/**\"\n",
+			want:    Error{Key: "scopes.rules[0].scope", Message: "scope is required"},
+		},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			root, _, _ := testDirs(tt)
+			path := writeConfig(tt, root, test.file, test.content)
+
+			_, err := LoadFrom(root)
+			var got *Error
+			if !errors.As(err, &got) {
+				tt.Fatalf("error = %v, want a *config.Error", err)
+			}
+			want := test.want
+			want.File = path
+			if want.Message == "" {
+				want.Message = got.Message
+			}
+			if *got != want {
+				tt.Errorf("error = %#v, want %#v", *got, want)
+			}
+		})
+	}
+}
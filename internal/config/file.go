@@ -0,0 +1,586 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/identity"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/infer"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/issue"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/semver"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/trailer"
+	t "github.com/GiulianoPoeta99/conventional_commits_cli/internal/types"
+
+	"gopkg.in/yaml.v3"
+)
+
+// typeCodePattern restricts commit type codes to what a Conventional Commit header accepts.
+var typeCodePattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// emojiCodePattern restricts emoji codes to GitHub shortcode characters.
+var emojiCodePattern = regexp.MustCompile(`^[a-z0-9_+-]+$`)
+
+// Error describes an invalid configuration value.
+// Key is a path such as "types[2].code"; Line is set when the position is known.
+type Error struct {
+	File    string
+	Line    int
+	Key     string
+	Message string
+}
+
+// Error formats the error as "file:line: key: message".
+func (e *Error) Error() string {
+	location := e.File
+	if e.Line > 0 {
+		location += fmt.Sprintf(":%d", e.Line)
+	}
+	if e.Key != "" {
+		return fmt.Sprintf("%s: %s: %s", location, e.Key, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", location, e.Message)
+}
+
+// fileType is a commit type entry as written in a configuration file.
+type fileType struct {
+	Code        string `json:"code" yaml:"code"`
+	Description string `json:"description" yaml:"description"`
+}
+
+// fileEmoji is an emoji entry as written in a configuration file.
+type fileEmoji struct {
+	Symbol      string `json:"symbol" yaml:"symbol"`
+	Code        string `json:"code" yaml:"code"`
+	Description string `json:"description" yaml:"description"`
+}
+
+// fileChangelog is the changelog section as written in a configuration file.
+type fileChangelog struct {
+	IssueURL  *string  `json:"issueUrl" yaml:"issueUrl"`
+	CommitURL *string  `json:"commitUrl" yaml:"commitUrl"`
+	Hidden    []string `json:"hidden" yaml:"hidden"`
+}
+
+// fileBump is the bump section as written in a configuration file.
+type fileBump struct {
+	TagPrefix *string           `json:"tagPrefix" yaml:"tagPrefix"`
+	Levels    map[string]string `json:"levels" yaml:"levels"`
+}
+
+// fileMessage is the message section as written in a configuration file.
+type fileMessage struct {
+	BodyInput       *string `json:"bodyInput" yaml:"bodyInput"`
+	WrapWidth       *int    `json:"wrapWidth" yaml:"wrapWidth"`
+	HeaderMaxLength *int    `json:"headerMaxLength" yaml:"headerMaxLength"`
+	Signoff         *bool   `json:"signoff" yaml:"signoff"`
+}
+
+// fileScopeRule is a scope rule as written in a configuration file.
+type fileScopeRule struct {
+	Glob  string `json:"glob" yaml:"glob"`
+	Scope string `json:"scope" yaml:"scope"`
+}
+
+// fileScopes is the scopes section as written in a configuration file.
+type fileScopes struct {
+	Rules []fileScopeRule `json:"rules" yaml:"rules"`
+}
+
+// fileTrailer is a trailer rule as written in a configuration file.
+type fileTrailer struct {
+	Key         string `json:"key" yaml:"key"`
+	Description string `json:"description" yaml:"description"`
+	Pattern     string `json:"pattern" yaml:"pattern"`
+	Required    bool   `json:"required" yaml:"required"`
+}
+
+// fileTeam is the team section as written in a configuration file.
+type fileTeam struct {
+	Roster *string `json:"roster" yaml:"roster"`
+}
+
+// fileBranchPattern is a branch pattern as written in a configuration file.
+type fileBranchPattern struct {
+	Pattern string `json:"pattern" yaml:"pattern"`
+	Ref     string `json:"ref" yaml:"ref"`
+}
+
+// fileIssues is the issues section as written in a configuration file.
+type fileIssues struct {
+	Syntaxes             []string            `json:"syntaxes" yaml:"syntaxes"`
+	FromBranch           *bool               `json:"fromBranch" yaml:"fromBranch"`
+	FromBranchUnattended *bool               `json:"fromBranchUnattended" yaml:"fromBranchUnattended"`
+	BranchPatterns       []fileBranchPattern `json:"branchPatterns" yaml:"branchPatterns"`
+	Relations            []string            `json:"relations" yaml:"relations"`
+}
+
+// fileTypeRule is a type inference rule as written in a configuration file.
+type fileTypeRule struct {
+	Type           string   `json:"type" yaml:"type"`
+	Reason         string   `json:"reason" yaml:"reason"`
+	Globs          []string `json:"globs" yaml:"globs"`
+	WhitespaceOnly bool     `json:"whitespaceOnly" yaml:"whitespaceOnly"`
+	Disabled       bool     `json:"disabled" yaml:"disabled"`
+}
+
+// file is the on-disk layout shared by the YAML and JSON formats.
+type file struct {
+	Types      []fileType          `json:"types" yaml:"types"`
+	Emojis     []fileEmoji         `json:"emojis" yaml:"emojis"`
+	TypeEmojis map[string][]string `json:"typeEmojis" yaml:"typeEmojis"`
+	Changelog  *fileChangelog      `json:"changelog" yaml:"changelog"`
+	Bump       *fileBump           `json:"bump" yaml:"bump"`
+	Message    *fileMessage        `json:"message" yaml:"message"`
+	Scopes     *fileScopes         `json:"scopes" yaml:"scopes"`
+	TypeRules  []fileTypeRule      `json:"typeRules" yaml:"typeRules"`
+	Issues     *fileIssues         `json:"issues" yaml:"issues"`
+	Team       *fileTeam           `json:"team" yaml:"team"`
+	Trailers   []fileTrailer       `json:"trailers" yaml:"trailers"`
+}
+
+// readFile decodes the configuration file at path, rejecting unknown keys.
+func readFile(path string) (file, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return file{}, err
+	}
+
+	var f file
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&f); err != nil {
+			return file{}, jsonError(path, content, err)
+		}
+		return f, nil
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return file{}, yamlError(path, content, err)
+	}
+	return f, nil
+}
+
+// yamlLinePattern matches the line number heading the YAML error messages.
+var yamlLinePattern = regexp.MustCompile(`^line (\d+): (.*)$`)
+
+// yamlUnknownFieldPattern matches the YAML error about an unknown key.
+var yamlUnknownFieldPattern = regexp.MustCompile(`^field (\S+) not found in type \S+$`)
+
+// yamlError converts a YAML decoding error into an Error with the line number and,
+// when the document could be read, the key path found at that line. Only the first of
+// several type errors is kept, like for the other errors.
+func yamlError(path string, content []byte, err error) error {
+	message := err.Error()
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+		message = typeErr.Errors[0]
+	}
+	e := &Error{File: path, Message: strings.TrimPrefix(message, "yaml: ")}
+
+	match := yamlLinePattern.FindStringSubmatch(e.Message)
+	if match == nil {
+		return e
+	}
+	e.Line, _ = strconv.Atoi(match[1])
+	e.Message = match[2]
+	if unknown := yamlUnknownFieldPattern.FindStringSubmatch(e.Message); unknown != nil {
+		e.Message = fmt.Sprintf("unknown key %q", unknown[1])
+	}
+
+	// Syntax errors leave no document to find the key in.
+	var root yaml.Node
+	if yaml.Unmarshal(content, &root) == nil {
+		e.Key = keyAt(&root, e.Line)
+	}
+	return e
+}
+
+// keyAt returns the path, such as "types[2].code", of the innermost key or sequence
+// item starting at line in the YAML document, or an empty string.
+func keyAt(root *yaml.Node, line int) string {
+	found := ""
+	var walk func(node *yaml.Node, key string)
+	walk = func(node *yaml.Node, key string) {
+		if node.Line == line && key != "" {
+			found = key
+		}
+		// Items of a flow collection share its line, so the collection's key is kept.
+		if node.Style&yaml.FlowStyle != 0 {
+			return
+		}
+		switch node.Kind {
+		case yaml.DocumentNode:
+			for _, child := range node.Content {
+				walk(child, key)
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				name := node.Content[i].Value
+				if key != "" {
+					name = key + "." + name
+				}
+				if node.Content[i].Line == line {
+					found = name
+				}
+				walk(node.Content[i+1], name)
+			}
+		case yaml.SequenceNode:
+			for i, child := range node.Content {
+				walk(child, fmt.Sprintf("%s[%d]", key, i))
+			}
+		}
+	}
+	walk(root, "")
+	return found
+}
+
+// jsonError converts a JSON decoding error into an Error with a line number.
+func jsonError(path string, content []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		return &Error{File: path, Line: lineAt(content, syntaxErr.Offset), Message: syntaxErr.Error()}
+	case errors.As(err, &typeErr):
+		return &Error{
+			File:    path,
+			Line:    lineAt(content, typeErr.Offset),
+			Key:     typeErr.Field,
+			Message: fmt.Sprintf("cannot use %s as %s", typeErr.Value, typeErr.Type),
+		}
+	default:
+		return &Error{File: path, Message: strings.TrimPrefix(err.Error(), "json: ")}
+	}
+}
+
+// lineAt returns the 1-based line number of the byte offset in content.
+func lineAt(content []byte, offset int64) int {
+	if offset > int64(len(content)) {
+		offset = int64(len(content))
+	}
+	return bytes.Count(content[:offset], []byte("\n")) + 1
+}
+
+// apply validates f and merges it into the configuration.
+// Entries whose code already exists replace the previous values; new codes are appended.
+func (c *Config) apply(path string, f file) error {
+	seen := map[string]bool{}
+	for i, entry := range f.Types {
+		key := fmt.Sprintf("types[%d]", i)
+		if !typeCodePattern.MatchString(entry.Code) {
+			return &Error{File: path, Key: key + ".code", Message: fmt.Sprintf("invalid commit type %q (use lowercase letters, digits and dashes)", entry.Code)}
+		}
+		if seen[entry.Code] {
+			return &Error{File: path, Key: key + ".code", Message: fmt.Sprintf("duplicate commit type %q", entry.Code)}
+		}
+		seen[entry.Code] = true
+
+		index := c.typeIndex(entry.Code)
+		if index < 0 {
+			if strings.TrimSpace(entry.Description) == "" {
+				return &Error{File: path, Key: key + ".description", Message: "description is required for new commit types"}
+			}
+			c.Types = append(c.Types, t.CommitType{Code: entry.Code, Description: entry.Description})
+		} else if entry.Description != "" {
+			c.Types[index].Description = entry.Description
+		}
+	}
+
+	seen = map[string]bool{}
+	for i, entry := range f.Emojis {
+		key := fmt.Sprintf("emojis[%d]", i)
+		if !emojiCodePattern.MatchString(entry.Code) {
+			return &Error{File: path, Key: key + ".code", Message: fmt.Sprintf("invalid emoji code %q (write it without colons)", entry.Code)}
+		}
+		if seen[entry.Code] {
+			return &Error{File: path, Key: key + ".code", Message: fmt.Sprintf("duplicate emoji %q", entry.Code)}
+		}
+		seen[entry.Code] = true
+
+		index := c.emojiIndex(entry.Code)
+		if index < 0 {
+			if entry.Symbol == "" {
+				return &Error{File: path, Key: key + ".symbol", Message: "symbol is required for new emojis"}
+			}
+			c.Emojis = append(c.Emojis, t.Emoji{Symbol: entry.Symbol, Code: entry.Code, Description: entry.Description})
+			continue
+		}
+		if entry.Symbol != "" {
+			c.Emojis[index].Symbol = entry.Symbol
+		}
+		if entry.Description != "" {
+			c.Emojis[index].Description = entry.Description
+		}
+	}
+
+	if len(f.TypeEmojis) > 0 {
+		typeEmojis := map[string][]string{}
+		for code, codes := range c.TypeEmojis {
+			typeEmojis[code] = codes
+		}
+
+		for code, codes := range f.TypeEmojis {
+			if c.typeIndex(code) < 0 {
+				return &Error{File: path, Key: "typeEmojis." + code, Message: fmt.Sprintf("unknown commit type %q", code)}
+			}
+			for i, emojiCode := range codes {
+				if c.emojiIndex(emojiCode) < 0 {
+					return &Error{File: path, Key: fmt.Sprintf("typeEmojis.%s[%d]", code, i), Message: fmt.Sprintf("unknown emoji %q", emojiCode)}
+				}
+			}
+			typeEmojis[code] = codes
+		}
+		c.TypeEmojis = typeEmojis
+	}
+
+	if f.Changelog != nil {
+		if f.Changelog.IssueURL != nil {
+			c.Changelog.IssueURL = *f.Changelog.IssueURL
+		}
+		if f.Changelog.CommitURL != nil {
+			c.Changelog.CommitURL = *f.Changelog.CommitURL
+		}
+		if f.Changelog.Hidden != nil {
+			for i, code := range f.Changelog.Hidden {
+				if c.typeIndex(code) < 0 {
+					return &Error{File: path, Key: fmt.Sprintf("changelog.hidden[%d]", i), Message: fmt.Sprintf("unknown commit type %q", code)}
+				}
+			}
+			c.Changelog.Hidden = f.Changelog.Hidden
+		}
+	}
+
+	if f.Bump != nil {
+		if f.Bump.TagPrefix != nil {
+			c.Bump.TagPrefix = *f.Bump.TagPrefix
+		}
+
+		levels := map[string]string{}
+		for code, level := range c.Bump.Levels {
+			levels[code] = level
+		}
+		for code, level := range f.Bump.Levels {
+			key := "bump.levels." + code
+			if c.typeIndex(code) < 0 {
+				return &Error{File: path, Key: key, Message: fmt.Sprintf("unknown commit type %q", code)}
+			}
+			if _, err := semver.ParseLevel(level); err != nil {
+				return &Error{File: path, Key: key, Message: err.Error()}
+			}
+			levels[code] = level
+		}
+		c.Bump.Levels = levels
+	}
+
+	if f.Message != nil {
+		if f.Message.BodyInput != nil {
+			if !slices.Contains(BodyInputs, *f.Message.BodyInput) {
+				return &Error{
+					File:    path,
+					Key:     "message.bodyInput",
+					Message: fmt.Sprintf("invalid body input %q (expected %s)", *f.Message.BodyInput, strings.Join(BodyInputs, ", ")),
+				}
+			}
+			c.Message.BodyInput = *f.Message.BodyInput
+		}
+		if f.Message.WrapWidth != nil {
+			if *f.Message.WrapWidth < 0 {
+				return &Error{File: path, Key: "message.wrapWidth", Message: "width cannot be negative (use 0 to disable wrapping)"}
+			}
+			c.Message.WrapWidth = *f.Message.WrapWidth
+		}
+		if f.Message.HeaderMaxLength != nil {
+			if *f.Message.HeaderMaxLength < 0 {
+				return &Error{File: path, Key: "message.headerMaxLength", Message: "length cannot be negative (use 0 to disable the check)"}
+			}
+			c.Message.HeaderMaxLength = *f.Message.HeaderMaxLength
+		}
+		if f.Message.Signoff != nil {
+			c.Message.Signoff = *f.Message.Signoff
+		}
+	}
+
+	// Scope rules of later files come first, so the nearest file has the last word.
+	if f.Scopes != nil && len(f.Scopes.Rules) > 0 {
+		rules := []infer.ScopeRule{}
+		for i, rule := range f.Scopes.Rules {
+			key := fmt.Sprintf("scopes.rules[%d]", i)
+			if _, err := infer.CompileGlob(rule.Glob); err != nil {
+				return &Error{File: path, Key: key + ".glob", Message: err.Error()}
+			}
+			if strings.TrimSpace(rule.Scope) == "" {
+				return &Error{File: path, Key: key + ".scope", Message: "scope is required"}
+			}
+			rules = append(rules, infer.ScopeRule{Glob: rule.Glob, Scope: rule.Scope})
+		}
+		c.Scopes.Rules = append(rules, c.Scopes.Rules...)
+	}
+
+	if f.Issues != nil {
+		if f.Issues.Syntaxes != nil {
+			for i, name := range f.Issues.Syntaxes {
+				if !slices.Contains(issue.Names(), name) {
+					return &Error{
+						File:    path,
+						Key:     fmt.Sprintf("issues.syntaxes[%d]", i),
+						Message: fmt.Sprintf("unknown syntax %q (expected %s)", name, strings.Join(issue.Names(), ", ")),
+					}
+				}
+			}
+			c.Issues.Syntaxes = f.Issues.Syntaxes
+		}
+		if f.Issues.FromBranch != nil {
+			c.Issues.FromBranch = *f.Issues.FromBranch
+		}
+		if f.Issues.FromBranchUnattended != nil {
+			c.Issues.FromBranchUnattended = *f.Issues.FromBranchUnattended
+		}
+		if f.Issues.BranchPatterns != nil {
+			patterns := []issue.BranchPattern{}
+			for i, entry := range f.Issues.BranchPatterns {
+				pattern := issue.BranchPattern{Pattern: entry.Pattern, Ref: entry.Ref}
+				if pattern.Ref == "" {
+					pattern.Ref = "{1}"
+				}
+				if _, err := issue.CompileBranchPattern(pattern); err != nil {
+					return &Error{File: path, Key: fmt.Sprintf("issues.branchPatterns[%d]", i), Message: err.Error()}
+				}
+				patterns = append(patterns, pattern)
+			}
+			c.Issues.BranchPatterns = patterns
+		}
+		if f.Issues.Relations != nil {
+			if len(f.Issues.Relations) == 0 {
+				return &Error{File: path, Key: "issues.relations", Message: "at least one relation is required"}
+			}
+			relations := []string{}
+			for i, name := range f.Issues.Relations {
+				relation, ok := issue.Relation(name)
+				if !ok {
+					return &Error{
+						File:    path,
+						Key:     fmt.Sprintf("issues.relations[%d]", i),
+						Message: fmt.Sprintf("unknown relation %q (expected %s)", name, strings.Join(issue.Relations, ", ")),
+					}
+				}
+				relations = append(relations, relation)
+			}
+			c.Issues.Relations = relations
+		}
+	}
+
+	// Trailer rules replace the previous rule of their key and new keys are appended.
+	for i, entry := range f.Trailers {
+		key := fmt.Sprintf("trailers[%d]", i)
+		if err := trailer.ValidKey(entry.Key); err != nil {
+			return &Error{File: path, Key: key + ".key", Message: err.Error()}
+		}
+		if _, err := regexp.Compile(entry.Pattern); err != nil {
+			return &Error{File: path, Key: key + ".pattern", Message: err.Error()}
+		}
+
+		rule := trailer.Rule{Key: entry.Key, Description: entry.Description, Pattern: entry.Pattern, Required: entry.Required}
+		if j := c.trailerIndex(entry.Key); j >= 0 {
+			c.Trailers[j] = rule
+		} else {
+			c.Trailers = append(c.Trailers, rule)
+		}
+	}
+
+	// The roster path is relative to the file declaring it; an empty path removes it.
+	if f.Team != nil && f.Team.Roster != nil {
+		roster := *f.Team.Roster
+		if roster != "" {
+			if !filepath.IsAbs(roster) {
+				roster = filepath.Join(filepath.Dir(path), roster)
+			}
+			if _, err := identity.ReadRoster(roster); err != nil {
+				return &Error{File: path, Key: "team.roster", Message: err.Error()}
+			}
+		}
+		c.Team.Roster = roster
+	}
+
+	// Type rules replace the previous rules of their type and take precedence over the others.
+	if len(f.TypeRules) > 0 {
+		rules := []infer.TypeRule{}
+		replaced := map[string]bool{}
+		for i, rule := range f.TypeRules {
+			key := fmt.Sprintf("typeRules[%d]", i)
+			if c.typeIndex(rule.Type) < 0 {
+				return &Error{File: path, Key: key + ".type", Message: fmt.Sprintf("unknown commit type %q", rule.Type)}
+			}
+			replaced[rule.Type] = true
+			if rule.Disabled {
+				continue
+			}
+
+			if len(rule.Globs) == 0 && !rule.WhitespaceOnly {
+				return &Error{File: path, Key: key, Message: "a rule needs globs or whitespaceOnly (use disabled to turn the type off)"}
+			}
+			for j, pattern := range rule.Globs {
+				if _, err := infer.CompileGlob(pattern); err != nil {
+					return &Error{File: path, Key: fmt.Sprintf("%s.globs[%d]", key, j), Message: err.Error()}
+				}
+			}
+			reason := rule.Reason
+			if reason == "" {
+				reason = "matched by " + path
+			}
+			rules = append(rules, infer.TypeRule{Type: rule.Type, Reason: reason, Globs: rule.Globs, WhitespaceOnly: rule.WhitespaceOnly})
+		}
+
+		for _, rule := range c.TypeRules {
+			if !replaced[rule.Type] {
+				rules = append(rules, rule)
+			}
+		}
+		c.TypeRules = rules
+	}
+
+	return nil
+}
+
+// typeIndex returns the position of the commit type with the given code, or -1.
+func (c *Config) typeIndex(code string) int {
+	for i, commitType := range c.Types {
+		if commitType.Code == code {
+			return i
+		}
+	}
+	return -1
+}
+
+// trailerIndex returns the position of the trailer rule with the given key, matched
+// case-insensitively, or -1.
+func (c *Config) trailerIndex(key string) int {
+	for i, rule := range c.Trailers {
+		if strings.EqualFold(rule.Key, key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// emojiIndex returns the position of the emoji with the given code, or -1.
+func (c *Config) emojiIndex(code string) int {
+	for i, emoji := range c.Emojis {
+		if emoji.Code == code {
+			return i
+		}
+	}
+	return -1
+}
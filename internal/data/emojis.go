@@ -1,6 +1,3 @@
-// Package data provides functions to retrieve commit types and emojis.
-// GetEmojis returns a slice of Emoji structures representing the available emojis
-// for enhancing commit messages with visual indicators.
 package data
 
 import (
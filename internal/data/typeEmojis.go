@@ -0,0 +1,19 @@
+package data
+
+// GetTypeEmojis returns the emoji codes recommended for each commit type.
+// The order of the codes defines the order in which suggestions are displayed.
+func GetTypeEmojis() map[string][]string {
+	return map[string][]string{
+		"feat":     {"sparkles", "rocket", "tada"},
+		"fix":      {"bug", "ambulance", "adhesive_bandage", "goal_net"},
+		"docs":     {"memo", "bulb", "pencil2"},
+		"style":    {"art", "lipstick"},
+		"refactor": {"recycle", "hammer", "truck"},
+		"perf":     {"zap", "chart_with_upwards_trend"},
+		"test":     {"white_check_mark", "test_tube"},
+		"build":    {"package", "construction_worker"},
+		"ci":       {"green_heart", "construction"},
+		"chore":    {"wrench", "bricks"},
+		"revert":   {"rewind", "coffin"},
+	}
+}
@@ -0,0 +1,131 @@
+// Package editor lets the user write text in their editor, the way git does for
+// commit messages.
+package editor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/git"
+)
+
+// Editor runs an editor command on a temporary file.
+type Editor struct {
+	// Command is the editor command line, interpreted by the shell like git does.
+	Command string
+	// Stdin, Stdout and Stderr are attached to the editor; nil means the process streams.
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Find returns the editor command git would use: $GIT_EDITOR, core.editor, $VISUAL
+// then $EDITOR, skipping those whose program cannot be found. It returns an empty
+// string when none is usable, so callers can fall back to terminal input.
+func Find(g git.Git) (string, error) {
+	candidates := []string{os.Getenv("GIT_EDITOR")}
+
+	coreEditor, err := g.Config("core.editor")
+	if err != nil {
+		return "", err
+	}
+	candidates = append(candidates, coreEditor, os.Getenv("VISUAL"), os.Getenv("EDITOR"))
+
+	for _, command := range candidates {
+		command = strings.TrimSpace(command)
+		if command == "" {
+			continue
+		}
+
+		// ":" is git's no-op editor, which would always leave the text unchanged.
+		if command == ":" {
+			return "", nil
+		}
+		program := strings.Trim(strings.Fields(command)[0], `"'`)
+		if _, err := exec.LookPath(program); err != nil {
+			continue
+		}
+		return command, nil
+	}
+
+	return "", nil
+}
+
+// Edit writes initial into a temporary file, opens it in the editor and returns the
+// content saved by the user, without removing comments.
+func (e *Editor) Edit(initial string) (string, error) {
+	file, err := os.CreateTemp("", "commit-body-*.txt")
+	if err != nil {
+		return "", err
+	}
+	path := file.Name()
+	defer os.Remove(path)
+
+	if _, err := file.WriteString(initial); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	// Like git, let the shell interpret the command so it may carry arguments.
+	cmd := exec.Command("sh", "-c", e.Command+` "$@"`, e.Command, path)
+	cmd.Stdin = e.Stdin
+	cmd.Stdout = e.Stdout
+	cmd.Stderr = e.Stderr
+	if cmd.Stdin == nil {
+		cmd.Stdin = os.Stdin
+	}
+	if cmd.Stdout == nil {
+		cmd.Stdout = os.Stdout
+	}
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("editor %q exited with status %d", e.Command, exitErr.ExitCode())
+		}
+		return "", fmt.Errorf("running editor %q: %w", e.Command, err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// Cleanup tidies edited text like `git commit --cleanup=strip`: it removes the lines
+// starting with '#', trailing whitespace, repeated blank lines and the blank lines
+// at both ends.
+func Cleanup(text string) string {
+	lines := []string{}
+	blank := false
+
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			blank = len(lines) > 0
+			continue
+		}
+		if blank {
+			lines = append(lines, "")
+			blank = false
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,43 @@
+package editor
+
+import (
+	"testing"
+
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/git"
+)
+
+// TestFind checks the order of the editor candidates, skipping the programs that
+// cannot be found.
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name                    string
+		gitEditor, core, visual string
+		editor                  string
+		want                    string
+	}{
+		{name: "none"},
+		{name: "git editor first", gitEditor: "sed -n p", core: "cat", editor: "true", want: "sed -n p"},
+		{name: "core editor", core: "cat", visual: "true", want: "cat"},
+		{name: "missing program skipped", gitEditor: "no-such-editor --wait", core: "cat", want: "cat"},
+		{name: "missing programs only", visual: "no-such-editor", editor: "no-such-editor-either"},
+		{name: "no-op editor", gitEditor: ":", editor: "cat"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("GIT_EDITOR", test.gitEditor)
+			t.Setenv("VISUAL", test.visual)
+			t.Setenv("EDITOR", test.editor)
+			g := git.NewFake()
+			g.Settings = map[string]string{"core.editor": test.core}
+
+			got, err := Find(g)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("Find() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
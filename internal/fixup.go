@@ -0,0 +1,35 @@
+package internal
+
+import "regexp"
+
+// Fixup is the kind of a transient commit that `git rebase --autosquash` folds into
+// the earlier commit named by its header.
+type Fixup string
+
+const (
+	// FixupPlain folds the changes and keeps the message of the target.
+	FixupPlain Fixup = "fixup"
+	// FixupSquash folds the changes and adds the body to the message of the target.
+	FixupSquash Fixup = "squash"
+	// FixupAmend folds the changes and replaces the message of the target with the body.
+	FixupAmend Fixup = "amend"
+)
+
+// fixupPattern matches the header prefix of the transient commits.
+var fixupPattern = regexp.MustCompile(`^(fixup|squash|amend)! `)
+
+// IsFixup reports whether the message is a transient fixup!, squash! or amend! commit.
+func IsFixup(message string) bool {
+	return fixupPattern.MatchString(message)
+}
+
+// FixupMessage returns the message of a kind commit for the commit whose header is
+// given. text is added to the message of the target by squash! and replaces it for
+// amend!; it is ignored by fixup!.
+func FixupMessage(kind Fixup, header, text string) string {
+	message := string(kind) + "! " + header
+	if kind != FixupPlain && text != "" {
+		message += "\n\n" + text
+	}
+	return message
+}
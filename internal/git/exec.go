@@ -0,0 +1,564 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Exec implements Git by running the git executable.
+type Exec struct {
+	// Dir is the working directory of the git commands; empty means the current one.
+	Dir string
+	// Stdout and Stderr receive the output of the commands that report to the user,
+	// such as the summary printed by `git commit`.
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// NewExec returns an Exec running in dir and reporting to the process output streams.
+func NewExec(dir string) *Exec {
+	return &Exec{Dir: dir, Stdout: os.Stdout, Stderr: os.Stderr}
+}
+
+// command builds a git command running in the configured directory. git runs in the C
+// locale, as the few messages recognised here are translated otherwise.
+func (g *Exec) command(args ...string) *exec.Cmd {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = g.Dir
+	cmd.Env = append(os.Environ(), "LC_ALL=C", "LANGUAGE=C")
+	return cmd
+}
+
+// exists reports whether revision names an object, through the exit status of
+// `git rev-parse --verify --quiet`.
+func (g *Exec) exists(revision string) (bool, error) {
+	err := g.command("rev-parse", "--quiet", "--verify", revision).Run()
+
+	// `git rev-parse --verify --quiet` exits with status 1 when the ref does not exist.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("git rev-parse: %w", err)
+	}
+	return true, nil
+}
+
+// run executes git with the given arguments and returns its trimmed standard output.
+// On failure the error includes git's standard error output.
+func (g *Exec) run(args ...string) (string, error) {
+	return g.runWithInput(nil, args...)
+}
+
+// runWithInput executes git like run, feeding input to its standard input.
+func (g *Exec) runWithInput(input io.Reader, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := g.command(args...)
+	cmd.Stdin = input
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], message)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
+
+// HasStagedChanges reports whether the index differs from HEAD.
+func (g *Exec) HasStagedChanges() (bool, error) {
+	err := g.command("diff", "--staged", "--quiet").Run()
+
+	// `git diff --quiet` exits with status 1 when there are differences.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("git diff: %w", err)
+	}
+	return false, nil
+}
+
+// StagedFiles returns the paths of the staged files, relative to the repository root.
+func (g *Exec) StagedFiles() ([]string, error) {
+	// NUL separators keep unusual file names unquoted.
+	output, err := g.run("diff", "--staged", "--name-only", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	for _, file := range strings.Split(output, "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// StagedDiff returns the staged changes as a unified diff.
+func (g *Exec) StagedDiff() (string, error) {
+	return g.run("diff", "--staged", "--no-color", "--no-ext-diff")
+}
+
+// changeStatuses names the statuses printed by `git diff --name-status`.
+var changeStatuses = map[string]string{"M": "modified", "D": "deleted", "T": "type changed", "A": "added"}
+
+// Unstaged reads the tracked changes with `git diff` and the untracked files with
+// `git ls-files`, counting the lines of untracked files as added. Unmerged files are
+// left out, as resolving conflicts is not a matter of staging.
+func (g *Exec) Unstaged() ([]Change, error) {
+	root, err := g.run("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+
+	// NUL separators keep unusual file names unquoted.
+	statuses, err := g.run("diff", "--no-renames", "--name-status", "-z")
+	if err != nil {
+		return nil, err
+	}
+	changes := []Change{}
+	index := map[string]int{}
+	fields := strings.Split(statuses, "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		status, path := fields[i], fields[i+1]
+		if status == "U" {
+			continue
+		}
+		name, ok := changeStatuses[status]
+		if !ok {
+			name = "modified"
+		}
+		index[path] = len(changes)
+		changes = append(changes, Change{Path: path, Status: name})
+	}
+
+	stats, err := g.run("diff", "--no-renames", "--numstat", "-z")
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range strings.Split(stats, "\x00") {
+		fields := strings.SplitN(record, "\t", 3)
+		if len(fields) < 3 {
+			continue
+		}
+		i, ok := index[fields[2]]
+		if !ok {
+			continue
+		}
+		// Binary files are counted as "-".
+		added, errAdded := strconv.Atoi(fields[0])
+		deleted, errDeleted := strconv.Atoi(fields[1])
+		if errAdded != nil || errDeleted != nil {
+			changes[i].Binary = true
+			continue
+		}
+		changes[i].Added, changes[i].Deleted = added, deleted
+	}
+
+	untracked, err := g.run("ls-files", "--others", "--exclude-standard", "--full-name", "-z", ":/")
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range strings.Split(untracked, "\x00") {
+		if path == "" {
+			continue
+		}
+		change := Change{Path: path, Status: "untracked"}
+		if content, err := os.ReadFile(filepath.Join(root, path)); err == nil {
+			switch {
+			case bytes.IndexByte(content, 0) >= 0:
+				change.Binary = true
+			case len(content) > 0:
+				change.Added = bytes.Count(content, []byte("\n"))
+				if content[len(content)-1] != '\n' {
+					change.Added++
+				}
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// Stage runs `git add`, with the paths anchored at the repository root so that it
+// works from any subdirectory.
+func (g *Exec) Stage(paths ...string) error {
+	args := []string{"add", "--"}
+	for _, path := range paths {
+		args = append(args, ":(top,literal)"+path)
+	}
+	_, err := g.run(args...)
+	return err
+}
+
+// Commit records the staged changes, forwarding git's output to Stdout and Stderr.
+func (g *Exec) Commit(message string, opts CommitOptions) error {
+	args := []string{"commit", "-m", message}
+	if opts.Amend {
+		args = append(args, "--amend")
+	}
+	if opts.AllowEmpty {
+		args = append(args, "--allow-empty")
+	}
+	switch {
+	case opts.SignKey != "":
+		args = append(args, "--gpg-sign="+opts.SignKey)
+	case opts.Sign != nil && *opts.Sign:
+		args = append(args, "--gpg-sign")
+	case opts.Sign != nil:
+		args = append(args, "--no-gpg-sign")
+	}
+
+	// The errors are shown to the user as they happen and kept to explain the failure.
+	var stderr bytes.Buffer
+	cmd := g.command(args...)
+	cmd.Stdout = g.Stdout
+	cmd.Stderr = &stderr
+	if g.Stderr != nil {
+		cmd.Stderr = io.MultiWriter(g.Stderr, &stderr)
+	}
+	err := cmd.Run()
+	if err == nil {
+		return nil
+	}
+
+	output := strings.TrimSpace(stderr.String())
+	if g.signs(opts) && signingFailed(output) {
+		format, _ := g.Config("gpg.format")
+		key := opts.SignKey
+		if key == "" {
+			key, _ = g.Config("user.signingKey")
+		}
+		return &SigningError{Format: format, Key: key, Output: output}
+	}
+
+	if output != "" {
+		lines := strings.Split(output, "\n")
+		return fmt.Errorf("git commit: %s", lines[len(lines)-1])
+	}
+	return fmt.Errorf("git commit: %w", err)
+}
+
+// signs reports whether a commit made with opts is signed.
+func (g *Exec) signs(opts CommitOptions) bool {
+	if opts.SignKey != "" {
+		return true
+	}
+	if opts.Sign != nil {
+		return *opts.Sign
+	}
+
+	value, _ := g.Config("commit.gpgSign")
+	switch strings.ToLower(value) {
+	case "true", "yes", "on", "1":
+		return true
+	}
+	return false
+}
+
+// Log returns the commits reachable from to but not from from, newest first.
+func (g *Exec) Log(from, to string, paths ...string) ([]Commit, error) {
+	if to == "" {
+		to = "HEAD"
+	}
+	revision := to
+	if from != "" {
+		revision = from + ".." + to
+	}
+
+	args := append([]string{"log", logFormat, revision, "--"}, paths...)
+	output, err := g.run(args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseLog(output)
+}
+
+// logFormat prints the fields read by parseLog. Fields are separated by US and
+// records by RS so messages can hold any text.
+const logFormat = "--format=%H%x1f%an <%ae>%x1f%aI%x1f%B%x1e"
+
+// parseLog reads the commits printed by git log with logFormat.
+func parseLog(output string) ([]Commit, error) {
+	commits := []Commit{}
+	for _, record := range strings.Split(output, "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+
+		fields := strings.SplitN(record, "\x1f", 4)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("git log: unexpected output %q", record)
+		}
+		commits = append(commits, Commit{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Date:    fields[2],
+			Message: strings.TrimRight(fields[3], "\n"),
+		})
+	}
+
+	return commits, nil
+}
+
+// Authors returns the identities of the authors and co-authors, most recent first.
+func (g *Exec) Authors() ([]string, error) {
+	// A repository without commits has no authors yet.
+	if born, err := g.exists("HEAD"); err != nil || !born {
+		return []string{}, err
+	}
+
+	// %aN and %aE apply .mailmap; the trailers follow the author, one per line.
+	output, err := g.run("log", "--format=%aN <%aE>%n%(trailers:key=Co-authored-by,valueonly)")
+	if err != nil {
+		return nil, err
+	}
+
+	authors := []string{}
+	seen := map[string]bool{}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || seen[line] {
+			continue
+		}
+		seen[line] = true
+		authors = append(authors, line)
+	}
+	return authors, nil
+}
+
+// Config returns the value of a configuration key, or an empty string when unset.
+func (g *Exec) Config(key string) (string, error) {
+	output, err := g.command("config", "--get", key).Output()
+
+	// `git config --get` exits with status 1 when the key is not set.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("git config: %w", err)
+	}
+	return strings.TrimRight(string(output), "\n"), nil
+}
+
+// CommitAt returns the commit at revision.
+func (g *Exec) CommitAt(revision string) (Commit, error) {
+	output, err := g.run("log", "-1", logFormat, revision, "--")
+	if err != nil {
+		return Commit{}, err
+	}
+	commits, err := parseLog(output)
+	if err != nil {
+		return Commit{}, err
+	}
+	if len(commits) == 0 {
+		return Commit{}, fmt.Errorf("git log: unknown revision %q", revision)
+	}
+	return commits[0], nil
+}
+
+// Upstream returns the upstream branch of the checked out branch, such as
+// "origin/main", or an empty string when it has none.
+func (g *Exec) Upstream() (string, error) {
+	// Detached heads have nothing to compare with: `git symbolic-ref --quiet` exits
+	// with status 1 for them.
+	branch, err := g.command("symbolic-ref", "--quiet", "HEAD").Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("git symbolic-ref: %w", err)
+	}
+
+	// Branches without upstream print an empty line.
+	return g.run("for-each-ref", "--format=%(upstream:short)", strings.TrimSpace(string(branch)))
+}
+
+// IsAncestor reports whether the commit ancestor is reachable from descendant.
+func (g *Exec) IsAncestor(ancestor, descendant string) (bool, error) {
+	err := g.command("merge-base", "--is-ancestor", ancestor, descendant).Run()
+
+	// `git merge-base --is-ancestor` exits with status 1 when it is not an ancestor.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("git merge-base: %w", err)
+	}
+	return true, nil
+}
+
+// Recent returns at most limit commits of HEAD, newest first.
+func (g *Exec) Recent(limit int) ([]Commit, error) {
+	output, err := g.run("log", "-n", strconv.Itoa(limit), logFormat, "HEAD", "--")
+	if err != nil {
+		return nil, err
+	}
+	return parseLog(output)
+}
+
+// Revert runs `git revert --no-commit`. A revert in progress is forgotten first with
+// `git revert --quit`, which keeps its changes in the index: `git revert --continue`
+// would commit it on its own.
+func (g *Exec) Revert(revision string) ([]string, error) {
+	reverting, err := g.Reverting()
+	if err != nil {
+		return nil, err
+	}
+	if reverting {
+		if _, err := g.run("revert", "--quit"); err != nil {
+			return nil, err
+		}
+	}
+
+	_, revertErr := g.run("revert", "--no-commit", revision)
+	if revertErr == nil {
+		return nil, nil
+	}
+
+	// A failure leaving unmerged paths behind is a conflict for the user to resolve.
+	conflicts, err := g.UnmergedFiles()
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) == 0 {
+		return nil, revertErr
+	}
+	return conflicts, nil
+}
+
+// Reverting reports whether REVERT_HEAD exists.
+func (g *Exec) Reverting() (bool, error) {
+	return g.exists("REVERT_HEAD")
+}
+
+// UnmergedFiles returns the paths whose conflicts are not resolved yet.
+func (g *Exec) UnmergedFiles() ([]string, error) {
+	output, err := g.run("diff", "--name-only", "--diff-filter=U", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	for _, file := range strings.Split(output, "\x00") {
+		if file != "" && !slices.Contains(files, file) {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// GitPath returns the absolute path of name inside the git directory, as resolved by
+// `git rev-parse --git-path`.
+func (g *Exec) GitPath(name string) (string, error) {
+	return g.run("rev-parse", "--path-format=absolute", "--git-path", name)
+}
+
+// CurrentBranch returns the name of the checked out branch, or an empty string
+// when HEAD is detached.
+func (g *Exec) CurrentBranch() (string, error) {
+	return g.run("branch", "--show-current")
+}
+
+// Tags returns the tags reachable from HEAD.
+func (g *Exec) Tags() ([]string, error) {
+	output, err := g.run("tag", "--list", "--merged", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	if output == "" {
+		return []string{}, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+// LatestTag returns the most recent tag reachable from revision, or an empty string
+// when there is none.
+func (g *Exec) LatestTag(revision string) (string, error) {
+	if revision == "" {
+		revision = "HEAD"
+	}
+
+	// `git describe` fails alike without tags and on real errors, so the tags are
+	// looked for first.
+	tags, err := g.run("tag", "--list", "--merged", revision)
+	if err != nil || tags == "" {
+		return "", err
+	}
+	return g.run("describe", "--tags", "--abbrev=0", revision)
+}
+
+// ExactTag returns the tag pointing exactly at revision, or an empty string.
+func (g *Exec) ExactTag(revision string) string {
+	tag, err := g.run("describe", "--tags", "--exact-match", revision)
+	if err != nil {
+		return ""
+	}
+	return tag
+}
+
+// CreateTag creates an annotated tag on HEAD with the given message.
+// The message is kept verbatim so Markdown headings are not taken for comments.
+func (g *Exec) CreateTag(name, message string) error {
+	_, err := g.runWithInput(strings.NewReader(message), "tag", "--annotate", "--cleanup=verbatim", "--file=-", name)
+	return err
+}
+
+// Autosquash runs `git rebase --interactive --autosquash` from the parent of target, or
+// from the root commit when target has none. The todo list and the messages of the
+// squashed commits are accepted as git prepares them, and local changes are stashed
+// during the rebase. git's output is forwarded to Stdout and Stderr.
+func (g *Exec) Autosquash(target string) error {
+	args := []string{"rebase", "--interactive", "--autosquash", "--autostash"}
+	hasParent, err := g.exists(target + "^")
+	if err != nil {
+		return err
+	}
+	if hasParent {
+		args = append(args, target+"^")
+	} else {
+		args = append(args, "--root")
+	}
+
+	var stderr bytes.Buffer
+	cmd := g.command(args...)
+	cmd.Env = append(cmd.Env, "GIT_SEQUENCE_EDITOR=true", "GIT_EDITOR=true")
+	cmd.Stdout = g.Stdout
+	cmd.Stderr = &stderr
+	if g.Stderr != nil {
+		cmd.Stderr = io.MultiWriter(g.Stderr, &stderr)
+	}
+	if err := cmd.Run(); err != nil {
+		if output := strings.TrimSpace(stderr.String()); output != "" {
+			lines := strings.Split(output, "\n")
+			return fmt.Errorf("git rebase: %s", lines[len(lines)-1])
+		}
+		return fmt.Errorf("git rebase: %w", err)
+	}
+	return nil
+}
+
+// HooksDir returns the absolute path of the directory where git looks for hooks.
+// It honours core.hooksPath and resolves to the common directory in linked worktrees.
+func (g *Exec) HooksDir() (string, error) {
+	return g.run("rev-parse", "--path-format=absolute", "--git-path", "hooks")
+}
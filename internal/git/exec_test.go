@@ -0,0 +1,303 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testRepo initialises a repository in a temporary directory, isolated from the user
+// and system configuration, and returns an Exec running in it.
+func testRepo(t *testing.T) *Exec {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(home, ".gitconfig"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	dir := t.TempDir()
+	g := &Exec{Dir: dir}
+	runGit(t, g, "init", "--quiet", "--initial-branch=main")
+	runGit(t, g, "config", "user.name", "Jane Doe")
+	runGit(t, g, "config", "user.email", "jane@example.com")
+	runGit(t, g, "config", "commit.gpgSign", "false")
+	runGit(t, g, "config", "tag.gpgSign", "false")
+	return g
+}
+
+// runGit runs git in the repository of g and returns its output, failing the test
+// when it fails.
+func runGit(t *testing.T, g *Exec, args ...string) string {
+	t.Helper()
+	output, err := g.run(args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return output
+}
+
+// writeFile writes content to name in the repository of g.
+func writeFile(t *testing.T, g *Exec, name, content string) {
+	t.Helper()
+	path := filepath.Join(g.Dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// commitFile writes content to name, stages it and commits it with message, returning
+// the hash of the commit.
+func commitFile(t *testing.T, g *Exec, name, content, message string) string {
+	t.Helper()
+	writeFile(t, g, name, content)
+	if err := g.Stage(name); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Commit(message, CommitOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	return runGit(t, g, "rev-parse", "HEAD")
+}
+
+// translated sets a German locale, so that git would translate its messages if it
+// did not run in the C locale.
+func translated(t *testing.T) {
+	t.Setenv("LANG", "de_DE.UTF-8")
+	t.Setenv("LC_ALL", "de_DE.UTF-8")
+	t.Setenv("LANGUAGE", "de")
+}
+
+// TestExecEmptyRepository checks the methods whose answer is empty before the first
+// commit, whatever the locale.
+func TestExecEmptyRepository(t *testing.T) {
+	g := testRepo(t)
+	translated(t)
+
+	authors, err := g.Authors()
+	if err != nil || len(authors) != 0 {
+		t.Errorf("Authors() = %q, %v, want none", authors, err)
+	}
+	if upstream, err := g.Upstream(); err != nil || upstream != "" {
+		t.Errorf("Upstream() = %q, %v, want none", upstream, err)
+	}
+	if staged, err := g.HasStagedChanges(); err != nil || staged {
+		t.Errorf("HasStagedChanges() = %v, %v, want false", staged, err)
+	}
+	if value, err := g.Config("commit.template"); err != nil || value != "" {
+		t.Errorf("Config() = %q, %v, want an unset key", value, err)
+	}
+}
+
+// TestExecStageAndCommit follows changes from the working tree to a commit.
+func TestExecStageAndCommit(t *testing.T) {
+	g := testRepo(t)
+	commitFile(t, g, "tracked.txt", "one\ntwo\n", "feat: add tracked")
+
+	writeFile(t, g, "tracked.txt", "one\n")
+	writeFile(t, g, "dir/new file.txt", "a\nb\nc")
+	writeFile(t, g, "blob.bin", "\x00\x01")
+	changes, err := g.Unstaged()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Change{
+		{Path: "tracked.txt", Status: "modified", Deleted: 1},
+		{Path: "blob.bin", Status: "untracked", Binary: true},
+		{Path: "dir/new file.txt", Status: "untracked", Added: 3},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("Unstaged() = %+v, want %+v", changes, want)
+	}
+
+	// Paths are anchored at the root, also from a subdirectory.
+	sub := &Exec{Dir: filepath.Join(g.Dir, "dir")}
+	if err := sub.Stage("tracked.txt", "dir/new file.txt"); err != nil {
+		t.Fatal(err)
+	}
+	files, err := g.StagedFiles()
+	if err != nil || !reflect.DeepEqual(files, []string{"dir/new file.txt", "tracked.txt"}) {
+		t.Errorf("StagedFiles() = %q, %v", files, err)
+	}
+	if staged, err := g.HasStagedChanges(); err != nil || !staged {
+		t.Errorf("HasStagedChanges() = %v, %v, want true", staged, err)
+	}
+	if diff, err := g.StagedDiff(); err != nil || !strings.Contains(diff, "-two") {
+		t.Errorf("StagedDiff() = %q, %v", diff, err)
+	}
+
+	message := "fix: trim the file\n\nBody.\n\nCo-authored-by: Bob <bob@example.com>"
+	if err := g.Commit(message, CommitOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	head, err := g.CommitAt("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head.Message != message || head.Author != "Jane Doe <jane@example.com>" {
+		t.Errorf("CommitAt(HEAD) = %+v", head)
+	}
+
+	commits, err := g.Log("", "")
+	if err != nil || len(commits) != 2 || commits[0].Hash != head.Hash {
+		t.Errorf("Log() = %+v, %v, want both commits newest first", commits, err)
+	}
+	recent, err := g.Recent(1)
+	if err != nil || len(recent) != 1 || recent[0].Hash != head.Hash {
+		t.Errorf("Recent(1) = %+v, %v", recent, err)
+	}
+	authors, err := g.Authors()
+	if err != nil || !reflect.DeepEqual(authors, []string{"Jane Doe <jane@example.com>", "Bob <bob@example.com>"}) {
+		t.Errorf("Authors() = %q, %v", authors, err)
+	}
+
+	// Amending without staged changes rewords HEAD.
+	if err := g.Commit("fix: trim the file again", CommitOptions{Amend: true}); err != nil {
+		t.Fatal(err)
+	}
+	if ancestor, err := g.IsAncestor(head.Hash, "HEAD"); err != nil || ancestor {
+		t.Errorf("IsAncestor(amended, HEAD) = %v, %v, want false", ancestor, err)
+	}
+	if ancestor, err := g.IsAncestor(commits[1].Hash, "HEAD"); err != nil || !ancestor {
+		t.Errorf("IsAncestor(first, HEAD) = %v, %v, want true", ancestor, err)
+	}
+}
+
+// TestExecUpstream checks the upstream of a tracking branch, a branch without one and
+// a detached head.
+func TestExecUpstream(t *testing.T) {
+	g := testRepo(t)
+	translated(t)
+	commitFile(t, g, "a.txt", "a\n", "feat: add a")
+
+	if upstream, err := g.Upstream(); err != nil || upstream != "" {
+		t.Errorf("Upstream() without upstream = %q, %v", upstream, err)
+	}
+
+	runGit(t, g, "branch", "base")
+	runGit(t, g, "branch", "--quiet", "--set-upstream-to=base")
+	if upstream, err := g.Upstream(); err != nil || upstream != "base" {
+		t.Errorf("Upstream() = %q, %v, want base", upstream, err)
+	}
+	if branch, err := g.CurrentBranch(); err != nil || branch != "main" {
+		t.Errorf("CurrentBranch() = %q, %v, want main", branch, err)
+	}
+
+	runGit(t, g, "checkout", "--quiet", "--detach")
+	if upstream, err := g.Upstream(); err != nil || upstream != "" {
+		t.Errorf("Upstream() when detached = %q, %v", upstream, err)
+	}
+	if branch, err := g.CurrentBranch(); err != nil || branch != "" {
+		t.Errorf("CurrentBranch() when detached = %q, %v", branch, err)
+	}
+}
+
+// TestExecTags checks the tags before and after tagging, whatever the locale.
+func TestExecTags(t *testing.T) {
+	g := testRepo(t)
+	translated(t)
+	first := commitFile(t, g, "a.txt", "a\n", "feat: add a")
+
+	if tag, err := g.LatestTag(""); err != nil || tag != "" {
+		t.Errorf("LatestTag() without tags = %q, %v", tag, err)
+	}
+
+	message := "## v1.0.0\n\n# Not a comment\n"
+	if err := g.CreateTag("v1.0.0", message); err != nil {
+		t.Fatal(err)
+	}
+	if got := runGit(t, g, "tag", "--list", "--format=%(contents)", "v1.0.0"); got != strings.TrimSuffix(message, "\n") {
+		t.Errorf("tag message %q, want %q kept verbatim", got, message)
+	}
+	commitFile(t, g, "b.txt", "b\n", "fix: add b")
+
+	if tag, err := g.LatestTag(""); err != nil || tag != "v1.0.0" {
+		t.Errorf("LatestTag() = %q, %v, want v1.0.0", tag, err)
+	}
+	if tag := g.ExactTag("HEAD"); tag != "" {
+		t.Errorf("ExactTag(HEAD) = %q, want none", tag)
+	}
+	if tag := g.ExactTag(first); tag != "v1.0.0" {
+		t.Errorf("ExactTag(first) = %q, want v1.0.0", tag)
+	}
+	if tags, err := g.Tags(); err != nil || !reflect.DeepEqual(tags, []string{"v1.0.0"}) {
+		t.Errorf("Tags() = %q, %v", tags, err)
+	}
+
+	// A tag not reachable from the revision does not describe it.
+	runGit(t, g, "checkout", "--quiet", "--orphan", "other")
+	runGit(t, g, "rm", "--quiet", "-r", "--cached", ".")
+	commitFile(t, g, "c.txt", "c\n", "feat: start over")
+	if tag, err := g.LatestTag("HEAD"); err != nil || tag != "" {
+		t.Errorf("LatestTag() on another history = %q, %v", tag, err)
+	}
+}
+
+// TestExecRevert checks a revert stopped by conflicts.
+func TestExecRevert(t *testing.T) {
+	g := testRepo(t)
+	commitFile(t, g, "a.txt", "one\n", "feat: add a")
+	middle := commitFile(t, g, "a.txt", "two\n", "feat: change a")
+	commitFile(t, g, "a.txt", "three\n", "feat: change a again")
+
+	if reverting, err := g.Reverting(); err != nil || reverting {
+		t.Errorf("Reverting() before = %v, %v", reverting, err)
+	}
+	conflicts, err := g.Revert(middle)
+	if err != nil || !reflect.DeepEqual(conflicts, []string{"a.txt"}) {
+		t.Fatalf("Revert() = %q, %v, want a conflict on a.txt", conflicts, err)
+	}
+	if reverting, err := g.Reverting(); err != nil || !reverting {
+		t.Errorf("Reverting() = %v, %v, want true", reverting, err)
+	}
+	if unmerged, err := g.UnmergedFiles(); err != nil || !reflect.DeepEqual(unmerged, []string{"a.txt"}) {
+		t.Errorf("UnmergedFiles() = %q, %v", unmerged, err)
+	}
+	path, err := g.GitPath("REVERT_HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("GitPath(REVERT_HEAD) = %q: %v", path, err)
+	}
+	if hooks, err := g.HooksDir(); err != nil || !filepath.IsAbs(hooks) || filepath.Base(hooks) != "hooks" {
+		t.Errorf("HooksDir() = %q, %v", hooks, err)
+	}
+}
+
+// TestExecAutosquash checks that fixup commits are squashed, including into the root
+// commit.
+func TestExecAutosquash(t *testing.T) {
+	g := testRepo(t)
+	root := commitFile(t, g, "a.txt", "a\n", "feat: add a")
+	commitFile(t, g, "b.txt", "b\n", "feat: add b")
+	commitFile(t, g, "a.txt", "a, fixed\n", "fixup! feat: add a")
+	writeFile(t, g, "b.txt", "local change\n")
+
+	if err := g.Autosquash(root); err != nil {
+		t.Fatal(err)
+	}
+	commits, err := g.Log("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	headers := []string{}
+	for _, c := range commits {
+		headers = append(headers, c.Header())
+	}
+	if !reflect.DeepEqual(headers, []string{"feat: add b", "feat: add a"}) {
+		t.Errorf("history after autosquash %q", headers)
+	}
+	if content, err := os.ReadFile(filepath.Join(g.Dir, "b.txt")); err != nil || string(content) != "local change\n" {
+		t.Errorf("local change %q, %v, want it kept", content, err)
+	}
+}
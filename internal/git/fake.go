@@ -0,0 +1,492 @@
+package git
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fake is an in-memory repository implementing Git, meant for tests.
+// Its fields can be set directly to describe the state of the repository.
+type Fake struct {
+	// Staged lists the staged paths; Commit fails when it is empty and clears it.
+	Staged []string
+	// Patch is the unified diff returned by StagedDiff.
+	Patch string
+	// Changes lists the changes not staged for commit; Stage moves them to Staged.
+	Changes []Change
+	// History holds the commits, newest first.
+	History []Commit
+	// TagTargets maps tag names to the hash of the commit they point at.
+	TagTargets map[string]string
+	// TagMessages records the messages of the annotated tags created with CreateTag.
+	TagMessages map[string]string
+	// Settings holds the configuration values returned by Config.
+	Settings map[string]string
+	// Branch is the checked out branch; empty means a detached HEAD.
+	Branch string
+	// UpstreamRef is the upstream branch returned by Upstream, e.g. "origin/main",
+	// and UpstreamHash the hash of the commit it points at.
+	UpstreamRef  string
+	UpstreamHash string
+	// Hooks is the hooks directory returned by HooksDir.
+	Hooks string
+	// Dir is the git directory under which GitPath resolves names.
+	Dir string
+	// Conflicts maps the hashes of the commits whose revert conflicts to the
+	// conflicting paths.
+	Conflicts map[string][]string
+	// Unmerged lists the paths whose conflicts are not resolved yet.
+	Unmerged []string
+	// Reverted lists the hashes of the commits reverted since the last Commit.
+	Reverted []string
+	// Author is recorded on the commits created with Commit.
+	Author string
+	// LastCommitOptions holds the options of the last successful Commit.
+	LastCommitOptions CommitOptions
+	// Now returns the date recorded on new commits; nil means time.Now.
+	Now func() time.Time
+	// Errors makes the named method (e.g. "Commit") fail with the given error.
+	Errors map[string]error
+}
+
+// NewFake returns an empty fake repository on the main branch.
+func NewFake() *Fake {
+	return &Fake{
+		TagTargets:  map[string]string{},
+		TagMessages: map[string]string{},
+		Settings:    map[string]string{},
+		Branch:      "main",
+		Hooks:       "/fake/.git/hooks",
+		Dir:         "/fake/.git",
+		Conflicts:   map[string][]string{},
+		Author:      "Fake Author <fake@example.com>",
+	}
+}
+
+// fail returns the error configured for method, if any.
+func (g *Fake) fail(method string) error {
+	return g.Errors[method]
+}
+
+// HasStagedChanges reports whether Staged is not empty.
+func (g *Fake) HasStagedChanges() (bool, error) {
+	if err := g.fail("HasStagedChanges"); err != nil {
+		return false, err
+	}
+	return len(g.Staged) > 0, nil
+}
+
+// StagedFiles returns a copy of Staged.
+func (g *Fake) StagedFiles() ([]string, error) {
+	if err := g.fail("StagedFiles"); err != nil {
+		return nil, err
+	}
+	return append([]string{}, g.Staged...), nil
+}
+
+// StagedDiff returns Patch.
+func (g *Fake) StagedDiff() (string, error) {
+	if err := g.fail("StagedDiff"); err != nil {
+		return "", err
+	}
+	return g.Patch, nil
+}
+
+// Unstaged returns a copy of Changes.
+func (g *Fake) Unstaged() ([]Change, error) {
+	if err := g.fail("Unstaged"); err != nil {
+		return nil, err
+	}
+	return append([]Change{}, g.Changes...), nil
+}
+
+// Stage moves the changes at paths from Changes to Staged.
+func (g *Fake) Stage(paths ...string) error {
+	if err := g.fail("Stage"); err != nil {
+		return err
+	}
+	for _, path := range paths {
+		i := slices.IndexFunc(g.Changes, func(c Change) bool { return c.Path == path })
+		if i < 0 {
+			return fmt.Errorf("pathspec %q did not match any files", path)
+		}
+		g.Changes = slices.Delete(g.Changes, i, i+1)
+		if !slices.Contains(g.Staged, path) {
+			g.Staged = append(g.Staged, path)
+		}
+	}
+	return nil
+}
+
+// Commit prepends a commit with the given message to History, or replaces HEAD when
+// amending, and clears Staged, Patch and Reverted. The options are recorded in
+// LastCommitOptions.
+func (g *Fake) Commit(message string, opts CommitOptions) error {
+	if err := g.fail("Commit"); err != nil {
+		return err
+	}
+	if len(g.Staged) == 0 && !opts.Amend && !opts.AllowEmpty {
+		return errors.New("nothing added to commit")
+	}
+	if opts.Amend && len(g.History) == 0 {
+		return errors.New("nothing to amend")
+	}
+
+	now := time.Now
+	if g.Now != nil {
+		now = g.Now
+	}
+
+	// Amending replaces HEAD with a new commit.
+	history := g.History
+	if opts.Amend {
+		history = history[1:]
+	}
+	sum := sha1.Sum([]byte(fmt.Sprintf("%d\x00%s", len(g.History), message)))
+	g.History = append([]Commit{{
+		Hash:    hex.EncodeToString(sum[:]),
+		Author:  g.Author,
+		Date:    now().Format(time.RFC3339),
+		Message: message,
+	}}, history...)
+	g.Staged = nil
+	g.Patch = ""
+	g.Reverted = nil
+	g.LastCommitOptions = opts
+	return nil
+}
+
+// CommitAt returns the commit of History at revision.
+func (g *Fake) CommitAt(revision string) (Commit, error) {
+	if err := g.fail("CommitAt"); err != nil {
+		return Commit{}, err
+	}
+	i, err := g.resolve(revision)
+	if err != nil {
+		return Commit{}, err
+	}
+	return g.History[i], nil
+}
+
+// Upstream returns UpstreamRef.
+func (g *Fake) Upstream() (string, error) {
+	if err := g.fail("Upstream"); err != nil {
+		return "", err
+	}
+	return g.UpstreamRef, nil
+}
+
+// IsAncestor reports whether ancestor is descendant or older, History being linear.
+func (g *Fake) IsAncestor(ancestor, descendant string) (bool, error) {
+	if err := g.fail("IsAncestor"); err != nil {
+		return false, err
+	}
+	a, err := g.resolve(ancestor)
+	if err != nil {
+		return false, err
+	}
+	d, err := g.resolve(descendant)
+	if err != nil {
+		return false, err
+	}
+	return a >= d, nil
+}
+
+// Recent returns the first limit commits of History.
+func (g *Fake) Recent(limit int) ([]Commit, error) {
+	if err := g.fail("Recent"); err != nil {
+		return nil, err
+	}
+	return append([]Commit{}, g.History[:min(limit, len(g.History))]...), nil
+}
+
+// Revert records the commit at revision in Reverted and stages a placeholder path
+// named after it, since the fake keeps no file contents. The paths listed in
+// Conflicts for the commit become Unmerged.
+func (g *Fake) Revert(revision string) ([]string, error) {
+	if err := g.fail("Revert"); err != nil {
+		return nil, err
+	}
+	i, err := g.resolve(revision)
+	if err != nil {
+		return nil, err
+	}
+
+	c := g.History[i]
+	g.Reverted = append(g.Reverted, c.Hash)
+	g.Staged = append(g.Staged, "revert-"+c.ShortHash())
+	if conflicts := g.Conflicts[c.Hash]; len(conflicts) > 0 {
+		g.Unmerged = append([]string{}, conflicts...)
+		return conflicts, nil
+	}
+	return nil, nil
+}
+
+// Reverting reports whether Reverted is not empty.
+func (g *Fake) Reverting() (bool, error) {
+	if err := g.fail("Reverting"); err != nil {
+		return false, err
+	}
+	return len(g.Reverted) > 0, nil
+}
+
+// UnmergedFiles returns a copy of Unmerged.
+func (g *Fake) UnmergedFiles() ([]string, error) {
+	if err := g.fail("UnmergedFiles"); err != nil {
+		return nil, err
+	}
+	return append([]string{}, g.Unmerged...), nil
+}
+
+// GitPath joins name to Dir.
+func (g *Fake) GitPath(name string) (string, error) {
+	if err := g.fail("GitPath"); err != nil {
+		return "", err
+	}
+	return path.Join(g.Dir, name), nil
+}
+
+// resolve returns the position in History of a revision: "HEAD", a tag, a branch
+// name or a hash prefix, optionally followed by "^" or "~N".
+func (g *Fake) resolve(revision string) (int, error) {
+	base, suffix := revision, ""
+	if i := strings.IndexAny(revision, "^~"); i >= 0 {
+		base, suffix = revision[:i], revision[i:]
+	}
+
+	// Each "^" or "~" moves one commit back; "~N" moves N commits back.
+	offset := 0
+	for len(suffix) > 0 {
+		marker := suffix[0]
+		suffix = suffix[1:]
+		digits := len(suffix) - len(strings.TrimLeft(suffix, "0123456789"))
+		if marker == '~' && digits > 0 {
+			n, _ := strconv.Atoi(suffix[:digits])
+			offset += n
+			suffix = suffix[digits:]
+			continue
+		}
+		offset++
+	}
+
+	index := -1
+	switch {
+	case base == "HEAD" || base == g.Branch:
+		index = 0
+	case g.TagTargets[base] != "" || (base != "" && base == g.UpstreamRef):
+		if base == g.UpstreamRef {
+			base = g.UpstreamHash
+		} else {
+			base = g.TagTargets[base]
+		}
+		fallthrough
+	default:
+		for i, c := range g.History {
+			if base != "" && strings.HasPrefix(c.Hash, base) {
+				index = i
+				break
+			}
+		}
+	}
+
+	if index < 0 || len(g.History) == 0 || index+offset >= len(g.History) {
+		return 0, fmt.Errorf("unknown revision %q", revision)
+	}
+	return index + offset, nil
+}
+
+// Log returns the commits between from and to. The fake keeps no file lists,
+// so paths are ignored.
+func (g *Fake) Log(from, to string, paths ...string) ([]Commit, error) {
+	if err := g.fail("Log"); err != nil {
+		return nil, err
+	}
+	if to == "" {
+		to = "HEAD"
+	}
+
+	start, err := g.resolve(to)
+	if err != nil {
+		return nil, err
+	}
+	end := len(g.History)
+	if from != "" {
+		if end, err = g.resolve(from); err != nil {
+			return nil, err
+		}
+	}
+	if end < start {
+		return []Commit{}, nil
+	}
+
+	return append([]Commit{}, g.History[start:end]...), nil
+}
+
+// Authors returns the authors of History and the Co-authored-by trailers of their
+// messages, most recent first. The fake knows no .mailmap.
+func (g *Fake) Authors() ([]string, error) {
+	if err := g.fail("Authors"); err != nil {
+		return nil, err
+	}
+
+	authors := []string{}
+	seen := map[string]bool{}
+	add := func(author string) {
+		if author != "" && !seen[author] {
+			seen[author] = true
+			authors = append(authors, author)
+		}
+	}
+	for _, c := range g.History {
+		add(c.Author)
+		for _, line := range strings.Split(c.Message, "\n") {
+			if value, ok := strings.CutPrefix(line, "Co-authored-by: "); ok {
+				add(strings.TrimSpace(value))
+			}
+		}
+	}
+	return authors, nil
+}
+
+// Config returns the value from Settings.
+func (g *Fake) Config(key string) (string, error) {
+	if err := g.fail("Config"); err != nil {
+		return "", err
+	}
+	return g.Settings[key], nil
+}
+
+// CurrentBranch returns Branch.
+func (g *Fake) CurrentBranch() (string, error) {
+	if err := g.fail("CurrentBranch"); err != nil {
+		return "", err
+	}
+	return g.Branch, nil
+}
+
+// Tags returns the names in TagTargets pointing at commits in History.
+func (g *Fake) Tags() ([]string, error) {
+	if err := g.fail("Tags"); err != nil {
+		return nil, err
+	}
+
+	tags := []string{}
+	for _, c := range g.History {
+		for tag, hash := range g.TagTargets {
+			if hash == c.Hash {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags, nil
+}
+
+// LatestTag returns the first tag found walking History back from revision.
+func (g *Fake) LatestTag(revision string) (string, error) {
+	if err := g.fail("LatestTag"); err != nil {
+		return "", err
+	}
+	if revision == "" {
+		revision = "HEAD"
+	}
+	if len(g.History) == 0 {
+		return "", nil
+	}
+
+	start, err := g.resolve(revision)
+	if err != nil {
+		return "", err
+	}
+	for _, c := range g.History[start:] {
+		for tag, hash := range g.TagTargets {
+			if hash == c.Hash {
+				return tag, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// ExactTag returns a tag pointing at revision, or an empty string.
+func (g *Fake) ExactTag(revision string) string {
+	index, err := g.resolve(revision)
+	if err != nil {
+		return ""
+	}
+	for tag, hash := range g.TagTargets {
+		if hash == g.History[index].Hash {
+			return tag
+		}
+	}
+	return ""
+}
+
+// CreateTag points a new tag at HEAD and records its message.
+func (g *Fake) CreateTag(name, message string) error {
+	if err := g.fail("CreateTag"); err != nil {
+		return err
+	}
+	if len(g.History) == 0 {
+		return errors.New("cannot tag an empty repository")
+	}
+	if _, exists := g.TagTargets[name]; exists {
+		return fmt.Errorf("tag %q already exists", name)
+	}
+
+	g.TagTargets[name] = g.History[0].Hash
+	g.TagMessages[name] = message
+	return nil
+}
+
+// Autosquash folds the fixup!, squash! and amend! commits made since target into the
+// commits whose header they name, like `git rebase --autosquash`: squash! adds its
+// body to the message and amend! replaces the message with it. Hashes are kept, as
+// the fake computes no trees.
+func (g *Fake) Autosquash(target string) error {
+	if err := g.fail("Autosquash"); err != nil {
+		return err
+	}
+	end, err := g.resolve(target)
+	if err != nil {
+		return err
+	}
+
+	// Replay the commits oldest first, as the rebase does.
+	kept := []Commit{}
+	for i := end; i >= 0; i-- {
+		c := g.History[i]
+		kind, header, _ := strings.Cut(c.Header(), "! ")
+		j := slices.IndexFunc(kept, func(k Commit) bool { return k.Header() == header })
+		if j < 0 || (kind != "fixup" && kind != "squash" && kind != "amend") {
+			kept = append(kept, c)
+			continue
+		}
+
+		_, body, _ := strings.Cut(c.Message, "\n\n")
+		switch {
+		case kind == "squash" && body != "":
+			kept[j].Message += "\n\n" + body
+		case kind == "amend" && body != "":
+			kept[j].Message = body
+		}
+	}
+
+	slices.Reverse(kept)
+	g.History = append(kept, g.History[end+1:]...)
+	return nil
+}
+
+// HooksDir returns Hooks.
+func (g *Fake) HooksDir() (string, error) {
+	if err := g.fail("HooksDir"); err != nil {
+		return "", err
+	}
+	return g.Hooks, nil
+}
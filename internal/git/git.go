@@ -0,0 +1,124 @@
+// Package git defines the repository operations needed by the assistant, with an
+// implementation running the git executable and an in-memory fake for tests.
+package git
+
+import (
+	"strings"
+)
+
+// Git is the set of repository operations used by the assistant.
+type Git interface {
+	// HasStagedChanges reports whether the index differs from HEAD.
+	HasStagedChanges() (bool, error)
+	// StagedFiles returns the paths of the staged files, relative to the repository root.
+	StagedFiles() ([]string, error)
+	// StagedDiff returns the staged changes as a unified diff.
+	StagedDiff() (string, error)
+	// Unstaged returns the files with changes not staged for commit, untracked files
+	// included, with their paths relative to the repository root.
+	Unstaged() ([]Change, error)
+	// Stage adds the changes of the files at paths, relative to the repository root,
+	// to the index. Deleted files are staged as removed.
+	Stage(paths ...string) error
+	// Commit records the staged changes with the given message. Signing failures are
+	// reported as a *SigningError.
+	Commit(message string, opts CommitOptions) error
+	// Log returns the commits reachable from to but not from from, newest first.
+	// An empty from lists the whole history of to; an empty to means HEAD.
+	// When paths are given, only the commits touching them are returned.
+	Log(from, to string, paths ...string) ([]Commit, error)
+	// CommitAt returns the commit at revision.
+	CommitAt(revision string) (Commit, error)
+	// Authors returns the "Name <email>" identities of the commit authors and of the
+	// co-authors named in Co-authored-by trailers, most recent first, as mapped by
+	// .mailmap and without duplicates.
+	Authors() ([]string, error)
+	// Config returns the value of a configuration key, or an empty string when unset.
+	Config(key string) (string, error)
+	// Upstream returns the upstream branch of the checked out branch, such as
+	// "origin/main", or an empty string when it has none.
+	Upstream() (string, error)
+	// IsAncestor reports whether the commit ancestor is reachable from descendant.
+	IsAncestor(ancestor, descendant string) (bool, error)
+	// Recent returns at most limit commits of HEAD, newest first.
+	Recent(limit int) ([]Commit, error)
+	// Revert applies the inverse of the commit at revision to the index and the working
+	// tree without committing, on top of the revert in progress if any. When it stops
+	// on conflicts, it returns the conflicting paths.
+	Revert(revision string) ([]string, error)
+	// Reverting reports whether a revert is in progress, i.e. whether REVERT_HEAD exists.
+	Reverting() (bool, error)
+	// UnmergedFiles returns the paths whose conflicts are not resolved yet.
+	UnmergedFiles() ([]string, error)
+	// GitPath returns the absolute path of name inside the git directory.
+	GitPath(name string) (string, error)
+	// CurrentBranch returns the name of the checked out branch, or an empty string
+	// when HEAD is detached.
+	CurrentBranch() (string, error)
+	// Tags returns the tags reachable from HEAD.
+	Tags() ([]string, error)
+	// LatestTag returns the most recent tag reachable from revision, or an empty
+	// string when there is none.
+	LatestTag(revision string) (string, error)
+	// ExactTag returns the tag pointing exactly at revision, or an empty string.
+	ExactTag(revision string) string
+	// CreateTag creates an annotated tag on HEAD with the given message.
+	CreateTag(name, message string) error
+	// Autosquash rebases the commits since target, target included, folding the fixup!,
+	// squash! and amend! commits into the commits they name, without opening an editor.
+	Autosquash(target string) error
+	// HooksDir returns the absolute path of the directory where git looks for hooks.
+	HooksDir() (string, error)
+}
+
+// CommitOptions adjusts how Commit records a commit.
+type CommitOptions struct {
+	// Amend replaces HEAD instead of creating a new commit.
+	Amend bool
+	// Sign forces signing on or off; nil follows commit.gpgSign.
+	Sign *bool
+	// SignKey is the key to sign with instead of user.signingKey; it implies signing.
+	SignKey string
+	// AllowEmpty records a commit without changes, such as an amend! commit only
+	// rewording its target.
+	AllowEmpty bool
+}
+
+// Change is a file with changes not staged for commit.
+type Change struct {
+	Path string
+	// Status is "modified", "deleted", "type changed" or "untracked".
+	Status string
+	// Added and Deleted count the changed lines; binary files count none.
+	Added   int
+	Deleted int
+	Binary  bool
+}
+
+// Commit is a commit read from the repository history.
+type Commit struct {
+	Hash    string
+	Author  string
+	Date    string
+	Message string
+}
+
+// ShortHash returns the abbreviated commit hash.
+func (c Commit) ShortHash() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+	return c.Hash
+}
+
+// Header returns the first line of the commit message.
+func (c Commit) Header() string {
+	header, _, _ := strings.Cut(c.Message, "\n")
+	return header
+}
+
+// Both implementations must satisfy the interface.
+var (
+	_ Git = (*Exec)(nil)
+	_ Git = (*Fake)(nil)
+)
@@ -0,0 +1,66 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// signingMarkers are found in the error output of git, run in the C locale, when a
+// commit cannot be signed.
+var signingMarkers = []string{
+	"failed to sign the data",
+	"user.signingkey",
+	"gpg.ssh.defaultKeyCommand",
+	"Couldn't load public key",
+	"ssh-keygen",
+	"No secret key",
+}
+
+// signingFailed reports whether the error output of git commit is about signing.
+func signingFailed(output string) bool {
+	for _, marker := range signingMarkers {
+		if strings.Contains(output, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// SigningError reports a commit that git could not sign.
+type SigningError struct {
+	// Format is the signature format set by gpg.format; empty means openpgp.
+	Format string
+	// Key is the signing key that was used, or empty for the default one.
+	Key string
+	// Output is the error output of git.
+	Output string
+}
+
+// Error explains the failure and how to fix it.
+func (e *SigningError) Error() string {
+	format := e.Format
+	if format == "" {
+		format = "openpgp"
+	}
+	key := "the default key"
+	if e.Key != "" {
+		key = "key " + e.Key
+	}
+	return fmt.Sprintf("could not sign the commit with %s (%s): %s", key, format, e.Hint())
+}
+
+// Hint tells the user how to make signing work.
+func (e *SigningError) Hint() string {
+	switch {
+	case e.Format == "ssh" && e.Key == "":
+		return "set user.signingKey to your SSH public key (git config user.signingKey ~/.ssh/id_ed25519.pub) or pass --gpg-sign=<key>"
+	case e.Format == "ssh":
+		return "check that the key file exists, that ssh-keygen is OpenSSH 8.2 or later, and that ssh-agent holds the private key (ssh-add -L)"
+	case e.Format == "x509":
+		return "check that gpgsm (or gpg.x509.program) has a certificate for the key (gpgsm --list-secret-keys)"
+	case strings.Contains(e.Output, "No secret key"):
+		return "gpg has no secret key for it; list the available ones with gpg --list-secret-keys --keyid-format=long and set user.signingKey or pass --gpg-sign=<key-id>"
+	}
+	return "check that gpg can sign (echo test | gpg --clearsign), that user.signingKey or --gpg-sign=<key-id> names one of your secret keys, " +
+		"and run export GPG_TTY=$(tty) so gpg can ask for the passphrase"
+}
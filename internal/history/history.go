@@ -0,0 +1,260 @@
+// Package history remembers the scopes used in each repository and ranks them by
+// frecency, a mix of how often and how recently they were used. It also remembers
+// the co-authors credited recently.
+package history
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	cfg "github.com/GiulianoPoeta99/conventional_commits_cli/internal/config"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/identity"
+)
+
+// Entry is a remembered scope.
+type Entry struct {
+	Scope    string    `json:"scope"`
+	Count    int       `json:"count"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// CoAuthor is a co-author credited recently.
+type CoAuthor struct {
+	Identity string    `json:"identity"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// MaxCoAuthors is the number of recent co-authors remembered.
+const MaxCoAuthors = 20
+
+// History is the scope history of one repository.
+type History struct {
+	Scopes []Entry `json:"scopes"`
+	// CoAuthors are the recent co-authors, most recent first.
+	CoAuthors []CoAuthor `json:"coAuthors,omitempty"`
+	// Imported is the hash of the newest commit mined by the last import, so the
+	// next import only reads the commits made since.
+	Imported string `json:"imported,omitempty"`
+}
+
+// Path returns the history file of the repository rooted at root, under the user
+// configuration directory. The file is named after the repository directory and a
+// hash of its absolute path, so clones of the same project have separate histories.
+func Path(root string) (string, error) {
+	dir, err := cfg.UserDir()
+	if err != nil {
+		return "", err
+	}
+
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+	sum := sha1.Sum([]byte(root))
+	name := fmt.Sprintf("%s-%s.json", filepath.Base(root), hex.EncodeToString(sum[:4]))
+	return filepath.Join(dir, "history", name), nil
+}
+
+// Load reads the history at path. A missing file is an empty history.
+func Load(path string) (*History, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &History{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	h := &History{}
+	if err := json.Unmarshal(content, h); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return h, nil
+}
+
+// Save writes the history to path, creating its directory if needed.
+// The file is written aside and renamed over path, so a concurrent run reads either
+// the previous history or the new one, never a partial file.
+func (h *History) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// Identities keep their angle brackets instead of \u003c escapes.
+	var content bytes.Buffer
+	encoder := json.NewEncoder(&content)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(h); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(content.Bytes())
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(file.Name(), 0o644)
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return nil
+}
+
+// Use records a use of scope at the given time. A multi-scope value such as
+// "api,web" records each of its scopes.
+func (h *History) Use(scope string, at time.Time) {
+	for _, name := range Split(scope) {
+		entry := h.entry(name)
+		entry.Count++
+		if at.After(entry.LastUsed) {
+			entry.LastUsed = at
+		}
+	}
+}
+
+// Add remembers scope without counting a use, and reports whether it was new.
+func (h *History) Add(scope string, at time.Time) bool {
+	if h.find(scope) >= 0 {
+		return false
+	}
+	h.Scopes = append(h.Scopes, Entry{Scope: scope, LastUsed: at})
+	return true
+}
+
+// Remove forgets scope and reports whether it was remembered.
+func (h *History) Remove(scope string) bool {
+	i := h.find(scope)
+	if i < 0 {
+		return false
+	}
+	h.Scopes = append(h.Scopes[:i], h.Scopes[i+1:]...)
+	return true
+}
+
+// Ranked returns the entries sorted by decreasing frecency at now, then by name.
+func (h *History) Ranked(now time.Time) []Entry {
+	entries := append([]Entry{}, h.Scopes...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		si, sj := Score(entries[i], now), Score(entries[j], now)
+		if si != sj {
+			return si > sj
+		}
+		return entries[i].Scope < entries[j].Scope
+	})
+	return entries
+}
+
+// Score returns the frecency of entry at now: its use count, plus one so that
+// scopes added by hand rank too, weighted by how recently it was last used.
+func Score(entry Entry, now time.Time) int {
+	age := now.Sub(entry.LastUsed)
+
+	weight := 10
+	switch {
+	case age <= 4*24*time.Hour:
+		weight = 100
+	case age <= 14*24*time.Hour:
+		weight = 70
+	case age <= 31*24*time.Hour:
+		weight = 50
+	case age <= 90*24*time.Hour:
+		weight = 30
+	}
+	return (entry.Count + 1) * weight
+}
+
+// Ago describes how long before now t was, e.g. "today" or "3 weeks ago".
+func Ago(t, now time.Time) string {
+	days := int(now.Sub(t).Hours() / 24)
+	switch {
+	case days <= 0:
+		return "today"
+	case days == 1:
+		return "yesterday"
+	case days < 14:
+		return fmt.Sprintf("%d days ago", days)
+	case days < 60:
+		return fmt.Sprintf("%d weeks ago", days/7)
+	case days < 730:
+		return fmt.Sprintf("%d months ago", days/30)
+	default:
+		return fmt.Sprintf("%d years ago", days/365)
+	}
+}
+
+// UseCoAuthor records that the identity id was credited as co-author at the given time,
+// moving it first and forgetting the oldest co-authors beyond MaxCoAuthors.
+// Identities are compared by email, ignoring case.
+func (h *History) UseCoAuthor(id string, at time.Time) {
+	recent := []CoAuthor{{Identity: id, LastUsed: at}}
+	for _, coAuthor := range h.CoAuthors {
+		if !sameIdentity(coAuthor.Identity, id) {
+			recent = append(recent, coAuthor)
+		}
+	}
+	if len(recent) > MaxCoAuthors {
+		recent = recent[:MaxCoAuthors]
+	}
+	h.CoAuthors = recent
+}
+
+// sameIdentity reports whether two "Name <email>" texts share the same email, or
+// are equal when one of them is not a valid identity.
+func sameIdentity(a, b string) bool {
+	ida, erra := identity.Parse(a)
+	idb, errb := identity.Parse(b)
+	if erra != nil || errb != nil {
+		return a == b
+	}
+	return ida.Same(idb)
+}
+
+// Split returns the scopes of a scope value, which may list several separated by commas.
+func Split(scope string) []string {
+	scopes := []string{}
+	for _, name := range strings.Split(scope, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			scopes = append(scopes, name)
+		}
+	}
+	return scopes
+}
+
+// entry returns the entry of scope, creating it if needed.
+func (h *History) entry(scope string) *Entry {
+	i := h.find(scope)
+	if i < 0 {
+		h.Scopes = append(h.Scopes, Entry{Scope: scope})
+		i = len(h.Scopes) - 1
+	}
+	return &h.Scopes[i]
+}
+
+// find returns the position of scope in the history, or -1.
+func (h *History) find(scope string) int {
+	for i, entry := range h.Scopes {
+		if entry.Scope == scope {
+			return i
+		}
+	}
+	return -1
+}
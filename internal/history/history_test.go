@@ -0,0 +1,244 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cfg "github.com/GiulianoPoeta99/conventional_commits_cli/internal/config"
+)
+
+// now is the reference time of the tests.
+var now = time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+
+// daysAgo returns the time the given number of days before now.
+func daysAgo(days int) time.Time {
+	return now.Add(-time.Duration(days) * 24 * time.Hour)
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", "repo.json")
+
+	h, err := Load(path)
+	if err != nil || len(h.Scopes) != 0 {
+		t.Fatalf("Load(missing) = %v, %v, want an empty history", h, err)
+	}
+
+	h.Use("api,web", daysAgo(1))
+	h.UseCoAuthor("Ada Lovelace <ada@example.com>", now)
+	h.Imported = "0123abc"
+	if err := h.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "<ada@example.com>") {
+		t.Errorf("saved file escapes the identity:\n%s", content)
+	}
+	if files, _ := os.ReadDir(filepath.Dir(path)); len(files) != 1 {
+		t.Errorf("history directory holds %d files, want the history alone", len(files))
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, h) {
+		t.Errorf("Load() = %+v, want %+v", loaded, h)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repo.json")
+	if err := os.WriteFile(path, []byte("{\"scopes\": ["), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.HasPrefix(err.Error(), path+": ") {
+		t.Errorf("Load() error = %v, want one naming the file", err)
+	}
+}
+
+// TestSaveConcurrent checks that concurrent saves leave a complete history behind.
+func TestSaveConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repo.json")
+
+	var wg sync.WaitGroup
+	for i := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h := &History{}
+			for j := range 50 + i*10 {
+				h.Use(fmt.Sprintf("scope-%d-%d", i, j), now)
+			}
+			if err := h.Save(path); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	h, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() after concurrent saves: %v", err)
+	}
+	if len(h.Scopes) < 50 {
+		t.Errorf("history has %d scopes, want one complete save", len(h.Scopes))
+	}
+	if files, _ := os.ReadDir(filepath.Dir(path)); len(files) != 1 {
+		t.Errorf("directory holds %d files, want the history alone", len(files))
+	}
+}
+
+func TestPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/config")
+
+	path, err := Path("/work/project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, name := filepath.Split(path)
+	if want := filepath.Join("/config", cfg.AppName, "history") + "/"; dir != want {
+		t.Errorf("Path() = %q, want a file in %s", path, want)
+	}
+	if !strings.HasPrefix(name, "project-") || !strings.HasSuffix(name, ".json") {
+		t.Errorf("Path() = %q, want a file named project-<hash>.json", path)
+	}
+	if other, _ := Path("/clone/project"); other == path {
+		t.Errorf("Path() = %q for two clones, want separate histories", path)
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		days  int
+		count int
+		want  int
+	}{
+		{0, 0, 100},
+		{4, 2, 300},
+		{5, 2, 210},
+		{14, 0, 70},
+		{15, 0, 50},
+		{31, 1, 100},
+		{32, 1, 60},
+		{90, 0, 30},
+		{91, 4, 50},
+	}
+
+	for _, test := range tests {
+		entry := Entry{Scope: "api", Count: test.count, LastUsed: daysAgo(test.days)}
+		if got := Score(entry, now); got != test.want {
+			t.Errorf("Score(%d uses, %d days ago) = %d, want %d", test.count, test.days, got, test.want)
+		}
+	}
+}
+
+func TestRanked(t *testing.T) {
+	h := &History{Scopes: []Entry{
+		{Scope: "old", Count: 9, LastUsed: daysAgo(200)},
+		{Scope: "web", Count: 1, LastUsed: daysAgo(2)},
+		{Scope: "api", Count: 1, LastUsed: daysAgo(3)},
+		{Scope: "cli", Count: 2, LastUsed: daysAgo(20)},
+		{Scope: "new", Count: 0, LastUsed: now},
+	}}
+
+	scopes := []string{}
+	for _, entry := range h.Ranked(now) {
+		scopes = append(scopes, entry.Scope)
+	}
+	if want := []string{"api", "web", "cli", "new", "old"}; !slices.Equal(scopes, want) {
+		t.Errorf("Ranked() = %q, want %q", scopes, want)
+	}
+	if h.Scopes[0].Scope != "old" {
+		t.Error("Ranked() reordered the history")
+	}
+}
+
+func TestUseAddRemove(t *testing.T) {
+	h := &History{}
+	h.Use("api, web", daysAgo(1))
+	h.Use("api", daysAgo(5))
+
+	want := []Entry{
+		{Scope: "api", Count: 2, LastUsed: daysAgo(1)},
+		{Scope: "web", Count: 1, LastUsed: daysAgo(1)},
+	}
+	if !reflect.DeepEqual(h.Scopes, want) {
+		t.Errorf("Scopes = %+v, want %+v", h.Scopes, want)
+	}
+
+	if h.Add("api", now) || !h.Add("docs", now) {
+		t.Error("Add() should only report new scopes")
+	}
+	if h.Remove("cli") || !h.Remove("web") {
+		t.Error("Remove() should only report remembered scopes")
+	}
+	if got := len(h.Scopes); got != 2 {
+		t.Errorf("history has %d scopes, want 2", got)
+	}
+}
+
+func TestUseCoAuthor(t *testing.T) {
+	h := &History{}
+	h.UseCoAuthor("Ada Lovelace <ada@example.com>", daysAgo(3))
+	h.UseCoAuthor("Alan Turing <alan@example.com>", daysAgo(2))
+	h.UseCoAuthor("Ada L. <ADA@example.com>", daysAgo(1))
+	h.UseCoAuthor("not an identity", now)
+
+	identities := []string{}
+	for _, coAuthor := range h.CoAuthors {
+		identities = append(identities, coAuthor.Identity)
+	}
+	want := []string{"not an identity", "Ada L. <ADA@example.com>", "Alan Turing <alan@example.com>"}
+	if !slices.Equal(identities, want) {
+		t.Errorf("CoAuthors = %q, want %q", identities, want)
+	}
+
+	for i := range MaxCoAuthors + 5 {
+		h.UseCoAuthor(fmt.Sprintf("Dev %d <dev%d@example.com>", i, i), now)
+	}
+	if len(h.CoAuthors) != MaxCoAuthors || h.CoAuthors[0].Identity != fmt.Sprintf("Dev %d <dev%d@example.com>", MaxCoAuthors+4, MaxCoAuthors+4) {
+		t.Errorf("CoAuthors has %d entries starting with %q, want the %d most recent", len(h.CoAuthors), h.CoAuthors[0].Identity, MaxCoAuthors)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := map[string][]string{
+		"":             {},
+		"api":          {"api"},
+		"api,web":      {"api", "web"},
+		" api , web ,": {"api", "web"},
+		",,":           {},
+	}
+	for scope, want := range tests {
+		if got := Split(scope); !slices.Equal(got, want) {
+			t.Errorf("Split(%q) = %q, want %q", scope, got, want)
+		}
+	}
+}
+
+func TestAgo(t *testing.T) {
+	tests := map[int]string{
+		0:   "today",
+		1:   "yesterday",
+		6:   "6 days ago",
+		21:  "3 weeks ago",
+		90:  "3 months ago",
+		800: "2 years ago",
+	}
+	for days, want := range tests {
+		if got := Ago(daysAgo(days), now); got != want {
+			t.Errorf("Ago(%d days) = %q, want %q", days, got, want)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// Package hook installs and removes the git hooks that run the assistant from `git commit`.
+package hook
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Names lists the hooks managed by the assistant.
+var Names = []string{"commit-msg", "prepare-commit-msg"}
+
+// Marker identifies the hook scripts written by the assistant.
+const Marker = "# conventional_commits_cli hook"
+
+// ChainedSuffix is appended to the name of a pre-existing hook that was moved aside
+// so the assistant's hook can run it first.
+const ChainedSuffix = ".local"
+
+// State describes what is currently installed for a hook.
+type State string
+
+const (
+	// StateMissing means no hook script exists.
+	StateMissing State = "not installed"
+	// StateInstalled means the assistant's hook script is installed.
+	StateInstalled State = "installed"
+	// StateForeign means a hook script not written by the assistant exists.
+	StateForeign State = "foreign hook"
+)
+
+// Status reports the state of one hook.
+type Status struct {
+	Name  string
+	Path  string
+	State State
+	// Chained is the path of the pre-existing hook run before the assistant, if any.
+	Chained string
+}
+
+// commands maps each hook to the assistant command it runs; $cli is the executable.
+var commands = map[string]string{
+	"commit-msg":         `"$cli" lint --quiet "$1"`,
+	"prepare-commit-msg": `"$cli" hook run prepare-commit-msg "$@"`,
+}
+
+// script returns the hook script for name, calling the executable at cli.
+// The previous hook, if any, runs first and its failure aborts the commit.
+func script(name, cli string) string {
+	return fmt.Sprintf(`#!/bin/sh
+%s
+# Installed by "commit hook install"; remove it with "commit hook uninstall".
+
+chained="$(dirname "$0")/%s%s"
+if [ -x "$chained" ]; then
+	"$chained" "$@" || exit $?
+fi
+
+cli=%s
+[ -x "$cli" ] || cli=commit
+exec %s
+`, Marker, name, ChainedSuffix, shellQuote(cli), commands[name])
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// isOurs reports whether the file at path is a hook written by the assistant.
+func isOurs(path string) bool {
+	content, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(content), Marker)
+}
+
+// exists reports whether a file exists at path.
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// Inspect returns the status of every managed hook in dir.
+func Inspect(dir string) []Status {
+	statuses := []Status{}
+
+	for _, name := range Names {
+		status := Status{Name: name, Path: filepath.Join(dir, name), State: StateMissing}
+		if exists(status.Path) {
+			status.State = StateForeign
+			if isOurs(status.Path) {
+				status.State = StateInstalled
+			}
+		}
+		if chained := status.Path + ChainedSuffix; exists(chained) {
+			status.Chained = chained
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+// Install writes the assistant's hooks into dir, calling the executable at cli.
+// A pre-existing foreign hook is renamed with ChainedSuffix and run before the assistant.
+// The scripts are written aside first and the completed steps are undone on failure,
+// so the hooks are either all installed or left as they were.
+func Install(dir, cli string) ([]Status, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	statuses := Inspect(dir)
+	for _, status := range statuses {
+		if status.State == StateForeign && status.Chained != "" {
+			return nil, fmt.Errorf(
+				"cannot chain %s: both %s and %s exist",
+				status.Name, status.Path, status.Chained,
+			)
+		}
+	}
+
+	temps := []string{}
+	defer func() {
+		for _, temp := range temps {
+			os.Remove(temp)
+		}
+	}()
+	for _, status := range statuses {
+		temp, err := writeTemp(dir, status.Name, script(status.Name, cli))
+		if err != nil {
+			return nil, err
+		}
+		temps = append(temps, temp)
+	}
+
+	undo := []func() error{}
+	rollback := func(err error) ([]Status, error) {
+		for i := len(undo) - 1; i >= 0; i-- {
+			if undoErr := undo[i](); undoErr != nil {
+				err = errors.Join(err, undoErr)
+			}
+		}
+		return nil, err
+	}
+	for i, status := range statuses {
+		// Moving the foreign hook back also replaces the assistant's script.
+		if status.State == StateForeign {
+			if err := os.Rename(status.Path, status.Path+ChainedSuffix); err != nil {
+				return rollback(err)
+			}
+			undo = append(undo, func() error { return os.Rename(status.Path+ChainedSuffix, status.Path) })
+		}
+
+		if err := os.Rename(temps[i], status.Path); err != nil {
+			return rollback(err)
+		}
+		if status.State == StateMissing {
+			undo = append(undo, func() error { return os.Remove(status.Path) })
+		}
+	}
+
+	return Inspect(dir), nil
+}
+
+// writeTemp writes content to a new executable file in dir, named after the hook but
+// ignored by git, and returns its path.
+func writeTemp(dir, name, content string) (string, error) {
+	file, err := os.CreateTemp(dir, "."+name+"-*")
+	if err != nil {
+		return "", err
+	}
+
+	_, err = file.WriteString(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(file.Name(), 0o755)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// Uninstall removes the assistant's hooks from dir and restores the chained hooks.
+// Foreign hooks are left untouched.
+func Uninstall(dir string) ([]Status, error) {
+	for _, status := range Inspect(dir) {
+		if status.State != StateInstalled {
+			continue
+		}
+
+		if err := os.Remove(status.Path); err != nil {
+			return nil, err
+		}
+		if status.Chained != "" {
+			if err := os.Rename(status.Chained, status.Path); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return Inspect(dir), nil
+}
+
+// WriteMessage replaces the message in the file git passes to prepare-commit-msg.
+// Everything from git's first comment line onwards (status, instructions and the
+// diff shown by --verbose) is kept below the new message.
+func WriteMessage(path, message string) error {
+	content, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	output := message + "\n"
+	lines := strings.Split(string(content), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			output += "\n" + strings.Join(lines[i:], "\n")
+			break
+		}
+	}
+
+	return os.WriteFile(path, []byte(output), 0o644)
+}
@@ -0,0 +1,190 @@
+package hook
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeExecutable writes a shell script at path.
+func writeExecutable(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+content), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readFile returns the content of path, or an empty string when it does not exist.
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+// states returns the state of every hook, with the chained hook's name when there is one.
+func states(statuses []Status) []string {
+	result := []string{}
+	for _, status := range statuses {
+		state := status.Name + ": " + string(status.State)
+		if status.Chained != "" {
+			state += " chaining " + filepath.Base(status.Chained)
+		}
+		result = append(result, state)
+	}
+	return result
+}
+
+// entries returns the names of the files in dir.
+func entries(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	return names
+}
+
+func TestInstallAndUninstall(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "hooks")
+	foreign := "echo foreign\n"
+
+	if got, want := states(Inspect(dir)), []string{"commit-msg: not installed", "prepare-commit-msg: not installed"}; !slices.Equal(got, want) {
+		t.Fatalf("Inspect() = %q, want %q", got, want)
+	}
+
+	statuses, err := Install(dir, "/opt/it's/commit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := states(statuses), []string{"commit-msg: installed", "prepare-commit-msg: installed"}; !slices.Equal(got, want) {
+		t.Errorf("Install() = %q, want %q", got, want)
+	}
+	script := readFile(t, filepath.Join(dir, "commit-msg"))
+	if !strings.Contains(script, Marker) || !strings.Contains(script, `cli='/opt/it'\''s/commit'`) {
+		t.Errorf("commit-msg script lacks the marker or the quoted executable:\n%s", script)
+	}
+	if info, err := os.Stat(filepath.Join(dir, "commit-msg")); err != nil || info.Mode().Perm()&0o100 == 0 {
+		t.Errorf("commit-msg is not executable: %v", err)
+	}
+
+	// Installing again keeps the assistant's scripts without chaining them.
+	if _, err := Install(dir, "/usr/bin/commit"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := entries(t, dir), []string{"commit-msg", "prepare-commit-msg"}; !slices.Equal(got, want) {
+		t.Errorf("hooks = %q, want %q", got, want)
+	}
+
+	// Uninstalling leaves a foreign hook alone.
+	writeExecutable(t, filepath.Join(dir, "prepare-commit-msg"), foreign)
+	statuses, err = Uninstall(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := states(statuses), []string{"commit-msg: not installed", "prepare-commit-msg: foreign hook"}; !slices.Equal(got, want) {
+		t.Errorf("Uninstall() = %q, want %q", got, want)
+	}
+
+	// Installing over the foreign hook chains it, and uninstalling restores it.
+	statuses, err = Install(dir, "/usr/bin/commit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := states(statuses), []string{"commit-msg: installed", "prepare-commit-msg: installed chaining prepare-commit-msg.local"}; !slices.Equal(got, want) {
+		t.Errorf("Install() = %q, want %q", got, want)
+	}
+	if got := readFile(t, filepath.Join(dir, "prepare-commit-msg.local")); got != "#!/bin/sh\n"+foreign {
+		t.Errorf("chained hook = %q, want the foreign hook", got)
+	}
+
+	if _, err := Uninstall(dir); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := entries(t, dir), []string{"prepare-commit-msg"}; !slices.Equal(got, want) {
+		t.Errorf("hooks = %q, want %q", got, want)
+	}
+	if got := readFile(t, filepath.Join(dir, "prepare-commit-msg")); got != "#!/bin/sh\n"+foreign {
+		t.Errorf("restored hook = %q, want the foreign hook", got)
+	}
+}
+
+// TestInstallConflict checks that a hook that cannot be chained leaves every hook as it was.
+func TestInstallConflict(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutable(t, filepath.Join(dir, "commit-msg"), "echo mine\n")
+	writeExecutable(t, filepath.Join(dir, "prepare-commit-msg"), "echo mine\n")
+	writeExecutable(t, filepath.Join(dir, "prepare-commit-msg.local"), "echo older\n")
+
+	if _, err := Install(dir, "/usr/bin/commit"); err == nil || !strings.Contains(err.Error(), "cannot chain prepare-commit-msg") {
+		t.Fatalf("Install() error = %v, want a chaining conflict", err)
+	}
+	if got, want := entries(t, dir), []string{"commit-msg", "prepare-commit-msg", "prepare-commit-msg.local"}; !slices.Equal(got, want) {
+		t.Errorf("hooks = %q, want %q", got, want)
+	}
+	if got := readFile(t, filepath.Join(dir, "commit-msg")); got != "#!/bin/sh\necho mine\n" {
+		t.Errorf("commit-msg = %q, want it untouched", got)
+	}
+}
+
+// TestChainedHookRuns runs the installed script and checks that the chained hook runs
+// first and that its failure stops the commit before the assistant runs.
+func TestChainedHookRuns(t *testing.T) {
+	dir := t.TempDir()
+	hooks := filepath.Join(dir, "hooks")
+	log := filepath.Join(dir, "log")
+	cli := filepath.Join(dir, "commit")
+	writeExecutable(t, cli, `echo "cli $*" >>"`+log+`"`+"\n")
+	if err := os.MkdirAll(hooks, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeExecutable(t, filepath.Join(hooks, "commit-msg"), `echo "local $*" >>"`+log+`"`+"\nexit ${FAIL:-0}\n")
+
+	if _, err := Install(hooks, cli); err != nil {
+		t.Fatal(err)
+	}
+
+	if output, err := exec.Command(filepath.Join(hooks, "commit-msg"), "MSG").CombinedOutput(); err != nil {
+		t.Fatalf("commit-msg: %v\n%s", err, output)
+	}
+	if got, want := readFile(t, log), "local MSG\ncli lint --quiet MSG\n"; got != want {
+		t.Errorf("log = %q, want %q", got, want)
+	}
+
+	os.Remove(log)
+	command := exec.Command(filepath.Join(hooks, "commit-msg"), "MSG")
+	command.Env = append(os.Environ(), "FAIL=3")
+	err := command.Run()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Errorf("commit-msg error = %v, want exit status 3", err)
+	}
+	if got, want := readFile(t, log), "local MSG\n"; got != want {
+		t.Errorf("log = %q, want %q", got, want)
+	}
+}
+
+func TestWriteMessage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+	comments := "# Please enter the commit message for your changes.\n# On branch main\n"
+	if err := os.WriteFile(path, []byte("\n"+comments), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteMessage(path, "feat: add the endpoint"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := readFile(t, path), "feat: add the endpoint\n\n"+comments; got != want {
+		t.Errorf("message file = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,175 @@
+// Package identity parses the "Name <email>" identities used by git trailers and
+// gathers the known ones from the repository.
+package identity
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Identity is a person as written in git trailers.
+type Identity struct {
+	Name  string
+	Email string
+}
+
+// pattern matches "Name <email>", allowing any spacing around the parts.
+var pattern = regexp.MustCompile(`^\s*([^<>]*?)\s*<\s*([^<>\s]+@[^<>\s]+)\s*>\s*$`)
+
+// mailmapEntryPattern matches the canonical name and email at the start of a .mailmap entry.
+var mailmapEntryPattern = regexp.MustCompile(`^([^<#]*?)\s*<([^>]+)>`)
+
+// Parse reads an identity in the "Name <email>" form.
+func Parse(text string) (Identity, error) {
+	if strings.TrimSpace(text) == "" {
+		return Identity{}, errors.New("identity cannot be empty")
+	}
+
+	match := pattern.FindStringSubmatch(text)
+	if match == nil {
+		return Identity{}, fmt.Errorf("invalid identity %q (expected Name <email>)", strings.TrimSpace(text))
+	}
+	if match[1] == "" {
+		return Identity{}, fmt.Errorf("identity %q has no name (expected Name <email>)", strings.TrimSpace(text))
+	}
+	return Identity{Name: strings.Join(strings.Fields(match[1]), " "), Email: match[2]}, nil
+}
+
+// String returns the identity in the "Name <email>" form.
+func (id Identity) String() string {
+	return id.Name + " <" + id.Email + ">"
+}
+
+// Same reports whether two identities share the same email, ignoring case.
+func (id Identity) Same(other Identity) bool {
+	return strings.EqualFold(id.Email, other.Email)
+}
+
+// Mailmap is the list of canonical identities declared in a .mailmap file.
+type Mailmap []Identity
+
+// ReadMailmap reads the canonical identities of a .mailmap file: the first name and
+// email of each entry. A missing file is an empty mailmap.
+func ReadMailmap(path string) (Mailmap, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Mailmap{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	mailmap := Mailmap{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Entries without a proper name only fix an email and are skipped.
+		match := mailmapEntryPattern.FindStringSubmatch(line)
+		if match == nil || match[1] == "" {
+			continue
+		}
+		mailmap = append(mailmap, Identity{Name: match[1], Email: match[2]})
+	}
+	return mailmap, scanner.Err()
+}
+
+// ReadRoster reads a team roster: one "Name <email>" identity per line, where blank
+// lines and lines starting with "#" are ignored.
+func ReadRoster(path string) ([]Identity, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	roster := []Identity{}
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		id, err := Parse(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		roster = append(roster, id)
+	}
+	return roster, scanner.Err()
+}
+
+// Resolve reads an identity in the "Name <email>" form, or completes a bare name or
+// email with the only known identity having it, ignoring case.
+func Resolve(text string, known []Identity) (Identity, error) {
+	return resolve(text, known, false)
+}
+
+// Complete resolves text like Resolve, also accepting the start of a name when only
+// one known identity has a name starting with it. It suits pickers, where the
+// completed identity is shown before being used.
+func Complete(text string, known []Identity) (Identity, error) {
+	return resolve(text, known, true)
+}
+
+// resolve implements Resolve and, with prefix, Complete.
+func resolve(text string, known []Identity, prefix bool) (Identity, error) {
+	if strings.ContainsAny(text, "<>") {
+		return Parse(text)
+	}
+
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return Identity{}, errors.New("identity cannot be empty")
+	}
+
+	// Exact names and emails win over names merely starting with the text.
+	matchers := []func(id Identity) bool{
+		func(id Identity) bool { return strings.EqualFold(id.Name, text) || strings.EqualFold(id.Email, text) },
+	}
+	if prefix {
+		matchers = append(matchers, func(id Identity) bool { return strings.HasPrefix(strings.ToLower(id.Name), strings.ToLower(text)) })
+	}
+	matches := []Identity{}
+	for _, matcher := range matchers {
+		for _, id := range known {
+			if matcher(id) && !contains(matches, id) {
+				matches = append(matches, id)
+			}
+		}
+		if len(matches) > 0 {
+			break
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Identity{}, fmt.Errorf("unknown person %q (expected Name <email>)", text)
+	case 1:
+		return matches[0], nil
+	}
+
+	names := []string{}
+	for _, id := range matches {
+		names = append(names, id.String())
+	}
+	return Identity{}, fmt.Errorf("%q is ambiguous (%s); write it as Name <email>", text, strings.Join(names, ", "))
+}
+
+// contains reports whether ids holds an identity with the same email as id.
+func contains(ids []Identity, id Identity) bool {
+	for _, other := range ids {
+		if other.Same(id) {
+			return true
+		}
+	}
+	return false
+}
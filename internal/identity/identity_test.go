@@ -0,0 +1,46 @@
+package identity
+
+import "testing"
+
+// TestResolve checks that flags only complete exact names and emails, while pickers
+// also complete the start of a name.
+func TestResolve(t *testing.T) {
+	known := []Identity{
+		{Name: "Jane Doe", Email: "jane@example.com"},
+		{Name: "Janet Roe", Email: "janet@example.com"},
+		{Name: "Bob", Email: "bob@example.com"},
+	}
+	tests := []struct {
+		text     string
+		resolve  string
+		complete string
+	}{
+		{text: "Eve <eve@example.com>", resolve: "Eve <eve@example.com>", complete: "Eve <eve@example.com>"},
+		{text: "jane doe", resolve: "Jane Doe <jane@example.com>", complete: "Jane Doe <jane@example.com>"},
+		{text: "BOB@example.com", resolve: "Bob <bob@example.com>", complete: "Bob <bob@example.com>"},
+		{text: "Janet R", complete: "Janet Roe <janet@example.com>"},
+		{text: "Ja"},
+		{text: "Eve"},
+	}
+
+	for _, test := range tests {
+		for _, c := range []struct {
+			name    string
+			resolve func(string, []Identity) (Identity, error)
+			want    string
+		}{
+			{"Resolve", Resolve, test.resolve},
+			{"Complete", Complete, test.complete},
+		} {
+			id, err := c.resolve(test.text, known)
+			switch {
+			case c.want == "" && err == nil:
+				t.Errorf("%s(%q) = %q, want an error", c.name, test.text, id)
+			case c.want != "" && err != nil:
+				t.Errorf("%s(%q): %v", c.name, test.text, err)
+			case c.want != "" && id.String() != c.want:
+				t.Errorf("%s(%q) = %q, want %q", c.name, test.text, id, c.want)
+			}
+		}
+	}
+}
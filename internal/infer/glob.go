@@ -0,0 +1,76 @@
+// Package infer guesses parts of the commit message from the staged changes.
+package infer
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Glob is a compiled path pattern. "*" matches within a path segment, "**" across
+// segments, "?" one character, and a trailing "/" everything below a directory.
+// Patterns without a "/" match the file name in any directory, like .gitignore.
+type Glob struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+// CompileGlob compiles pattern.
+func CompileGlob(pattern string) (*Glob, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return nil, errors.New("empty glob")
+	}
+
+	source := pattern
+	if strings.HasSuffix(source, "/") {
+		source += "**"
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !strings.Contains(strings.TrimSuffix(pattern, "/"), "/") {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(source); {
+		switch {
+		case strings.HasPrefix(source[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 3
+		case strings.HasPrefix(source[i:], "**"):
+			b.WriteString("(.*)")
+			i += 2
+		case source[i] == '*':
+			b.WriteString("([^/]*)")
+			i++
+		case source[i] == '?':
+			b.WriteString("[^/]")
+			i++
+		default:
+			b.WriteString(regexp.QuoteMeta(source[i : i+1]))
+			i++
+		}
+	}
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid glob %q: %w", pattern, err)
+	}
+	return &Glob{pattern: pattern, re: re}, nil
+}
+
+// String returns the pattern the glob was compiled from.
+func (g *Glob) String() string {
+	return g.pattern
+}
+
+// Match reports whether path matches the glob and returns the text matched by each
+// "*" and "**" wildcard, in order.
+func (g *Glob) Match(path string) ([]string, bool) {
+	match := g.re.FindStringSubmatch(path)
+	if match == nil {
+		return nil, false
+	}
+	return match[1:], true
+}
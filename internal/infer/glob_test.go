@@ -0,0 +1,74 @@
+package infer
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompileGlob(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		path     string
+		match    bool
+		captures []string
+	}{
+		// Patterns without a "/" match the file name in any directory.
+		{pattern: "*.md", path: "README.md", match: true, captures: []string{"README"}},
+		{pattern: "*.md", path: "docs/guide/intro.md", match: true, captures: []string{"intro"}},
+		{pattern: "*.md", path: "README.mdx", match: false},
+		{pattern: "go.mod", path: "tools/go.mod", match: true, captures: []string{}},
+		{pattern: "?.go", path: "a.go", match: true, captures: []string{}},
+		{pattern: "?.go", path: "ab.go", match: false},
+
+		// Patterns with a "/" are anchored at the root and "*" stays within a segment.
+		{pattern: "docs/*.md", path: "docs/intro.md", match: true, captures: []string{"intro"}},
+		{pattern: "docs/*.md", path: "docs/guide/intro.md", match: false},
+		{pattern: "docs/*.md", path: "site/docs/intro.md", match: false},
+
+		// "**/" matches any number of directories, including none.
+		{pattern: "src/**/*.go", path: "src/main.go", match: true, captures: []string{"main"}},
+		{pattern: "src/**/*.go", path: "src/a/b/main.go", match: true, captures: []string{"main"}},
+		{pattern: "src/**", path: "src/a/b/main.go", match: true, captures: []string{"a/b/main.go"}},
+		{pattern: "src/**", path: "lib/src/main.go", match: false},
+
+		// A trailing "/" matches everything below the directory.
+		{pattern: "packages/*/", path: "packages/api/src/index.ts", match: true, captures: []string{"api", "src/index.ts"}},
+		{pattern: "packages/*/", path: "packages/api", match: false},
+		{pattern: "test/", path: "test/helpers.go", match: true, captures: []string{"helpers.go"}},
+		{pattern: "test/", path: "pkg/test/helpers.go", match: true, captures: []string{"helpers.go"}},
+		{pattern: "test/", path: "testing/helpers.go", match: false},
+		{pattern: ".github/workflows/", path: ".github/workflows/ci.yml", match: true, captures: []string{"ci.yml"}},
+		{pattern: ".github/workflows/", path: "sub/.github/workflows/ci.yml", match: false},
+
+		// Regular expression characters are literal.
+		{pattern: "a+b.(c)", path: "a+b.(c)", match: true, captures: []string{}},
+		{pattern: "a+b.(c)", path: "aab.(c)", match: false},
+	}
+
+	for _, test := range tests {
+		glob, err := CompileGlob(test.pattern)
+		if err != nil {
+			t.Fatalf("CompileGlob(%q): %v", test.pattern, err)
+		}
+		captures, ok := glob.Match(test.path)
+		if ok != test.match || !slices.Equal(captures, test.captures) {
+			t.Errorf("%q.Match(%q) = %q, %v, want %q, %v", test.pattern, test.path, captures, ok, test.captures, test.match)
+		}
+	}
+}
+
+func TestCompileGlobErrors(t *testing.T) {
+	for _, pattern := range []string{"", "  "} {
+		if _, err := CompileGlob(pattern); err == nil {
+			t.Errorf("CompileGlob(%q) succeeded, want an error", pattern)
+		}
+	}
+
+	glob, err := CompileGlob("packages/*/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if glob.String() != "packages/*/" {
+		t.Errorf("String() = %q, want the pattern", glob.String())
+	}
+}
@@ -0,0 +1,245 @@
+package infer
+
+import (
+	"bufio"
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ScopeRule maps the files matching a glob to a scope.
+// In Scope, "{1}", "{2}", ... stand for the text matched by the wildcards of the glob,
+// so "packages/*/" with "{1}" names the scope after the package directory.
+type ScopeRule struct {
+	Glob  string
+	Scope string
+}
+
+// ScopeSuggestion is a scope proposed for the staged files.
+type ScopeSuggestion struct {
+	Scope string
+	// Files are the staged files attributed to the scope.
+	Files []string
+	// Reason explains how the scope was derived from the first of Files.
+	Reason string
+}
+
+// placeholderPattern matches the wildcard references of ScopeRule.Scope.
+var placeholderPattern = regexp.MustCompile(`\{(\d+)\}`)
+
+// cargoNamePattern matches the name of a crate in Cargo.toml.
+var cargoNamePattern = regexp.MustCompile(`^\s*name\s*=\s*"([^"]+)"`)
+
+// goMajorPattern matches the major version suffix of a Go module path.
+var goMajorPattern = regexp.MustCompile(`^v[0-9]+$`)
+
+// Scopes proposes scopes for files, given relative to the repository root.
+// Each file gets the scope of the first source that applies: the rules, in order,
+// then the nearest package.json, go.mod or Cargo.toml below the root, then the
+// Go package of the file, then its top-level directory. Files at the root of the
+// repository get no scope. Suggestions are ranked by number of files.
+func Scopes(root string, files []string, rules []ScopeRule) ([]ScopeSuggestion, error) {
+	globs := make([]*Glob, len(rules))
+	for i, rule := range rules {
+		glob, err := CompileGlob(rule.Glob)
+		if err != nil {
+			return nil, err
+		}
+		globs[i] = glob
+	}
+
+	s := &scoper{root: root, modules: map[string]module{}}
+	suggestions := []ScopeSuggestion{}
+	index := map[string]int{}
+
+	for _, file := range files {
+		file = filepath.ToSlash(file)
+
+		scope, reason := "", ""
+		for i, glob := range globs {
+			if captures, ok := glob.Match(file); ok {
+				scope, reason = expandScope(rules[i].Scope, captures), "matches "+glob.String()
+				break
+			}
+		}
+		if scope == "" {
+			scope, reason = s.infer(file)
+		}
+		if scope == "" {
+			continue
+		}
+
+		if i, ok := index[scope]; ok {
+			suggestions[i].Files = append(suggestions[i].Files, file)
+			continue
+		}
+		index[scope] = len(suggestions)
+		suggestions = append(suggestions, ScopeSuggestion{Scope: scope, Files: []string{file}, Reason: reason})
+	}
+
+	// Rank by number of files, keeping the order of the files for ties.
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return len(suggestions[i].Files) > len(suggestions[j].Files)
+	})
+	return suggestions, nil
+}
+
+// expandScope replaces the wildcard references of scope with captures.
+func expandScope(scope string, captures []string) string {
+	return placeholderPattern.ReplaceAllStringFunc(scope, func(ref string) string {
+		n, _ := strconv.Atoi(ref[1 : len(ref)-1])
+		if n < 1 || n > len(captures) {
+			return ""
+		}
+		return strings.Trim(captures[n-1], "/")
+	})
+}
+
+// module is a package manifest found in a directory.
+type module struct {
+	name     string
+	manifest string
+}
+
+// scoper derives scopes from the repository layout, caching the manifests it reads.
+type scoper struct {
+	root    string
+	modules map[string]module
+}
+
+// infer derives the scope of file from the repository layout.
+func (s *scoper) infer(file string) (string, string) {
+	dir := path.Dir(file)
+	if dir == "." {
+		return "", ""
+	}
+
+	// The nearest module below the root names the scope.
+	for d := dir; d != "."; d = path.Dir(d) {
+		if m := s.module(d); m.name != "" {
+			return m.name, "module " + path.Join(d, m.manifest)
+		}
+	}
+
+	// Go files are named after their package.
+	if strings.HasSuffix(file, ".go") {
+		name := goPackage(filepath.Join(s.root, filepath.FromSlash(file)))
+		if name == "" || name == "main" {
+			name = path.Base(dir)
+		}
+		return name, "Go package " + dir
+	}
+
+	top, _, _ := strings.Cut(dir, "/")
+	return strings.TrimPrefix(top, "."), "directory " + top + "/"
+}
+
+// module returns the manifest found in dir, if any.
+func (s *scoper) module(dir string) module {
+	if m, ok := s.modules[dir]; ok {
+		return m
+	}
+
+	m := module{}
+	base := filepath.Join(s.root, filepath.FromSlash(dir))
+	readers := []struct {
+		manifest string
+		read     func(path string) string
+	}{
+		{"package.json", packageJSONName},
+		{"go.mod", goModuleName},
+		{"Cargo.toml", cargoName},
+	}
+	for _, reader := range readers {
+		if name := reader.read(filepath.Join(base, reader.manifest)); name != "" {
+			m = module{name: name, manifest: reader.manifest}
+			break
+		}
+	}
+
+	s.modules[dir] = m
+	return m
+}
+
+// packageJSONName returns the package name in a package.json, without its npm scope.
+func packageJSONName(file string) string {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+
+	var manifest struct {
+		Name string `json:"name"`
+	}
+	if json.Unmarshal(content, &manifest) != nil {
+		return ""
+	}
+	if i := strings.LastIndex(manifest.Name, "/"); i >= 0 {
+		return manifest.Name[i+1:]
+	}
+	return manifest.Name
+}
+
+// goModuleName returns the last element of the module path in a go.mod,
+// skipping a major version suffix such as "/v2".
+func goModuleName(file string) string {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "module" {
+			continue
+		}
+
+		elements := strings.Split(strings.Trim(fields[1], `"`), "/")
+		name := elements[len(elements)-1]
+		if goMajorPattern.MatchString(name) && len(elements) > 1 {
+			name = elements[len(elements)-2]
+		}
+		return name
+	}
+	return ""
+}
+
+// cargoName returns the crate name in the [package] section of a Cargo.toml.
+func cargoName(file string) string {
+	f, err := os.Open(file)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			section = line
+			continue
+		}
+		if match := cargoNamePattern.FindStringSubmatch(line); match != nil && section == "[package]" {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+// goPackage returns the package name of a Go file, without the "_test" suffix
+// of external test packages, or an empty string when it cannot be read.
+func goPackage(file string) string {
+	f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.PackageClauseOnly)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(f.Name.Name, "_test")
+}
@@ -0,0 +1,146 @@
+package infer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeFiles creates the files, given by path relative to root, with their content.
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestScopes(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"packages/web/package.json":   `{"name": "@acme/web"}`,
+		"tools/go.mod":                "module example.com/acme/tools/v2\n\ngo 1.24\n",
+		"crates/parser/Cargo.toml":    "[dependencies]\nname = \"serde\"\n\n[package]\nname = \"parser-core\"\n",
+		"internal/render/render.go":   "// Package renderer draws.\npackage renderer\n",
+		"internal/render/x_test.go":   "package renderer_test\n",
+		"cmd/serve/main.go":           "package main\n",
+		"packages/web/src/app.ts":     "",
+		"tools/cmd/lint/lint.go":      "package main\n",
+		"crates/parser/src/lib.rs":    "",
+		"apps/mobile/src/index.ts":    "",
+		"apps/mobile/package.json":    `{"name": "mobile-app"}`,
+		"docs/guide.md":               "",
+		".github/workflows/ci.yml":    "",
+		"README.md":                   "",
+		"vendor/lib/broken/broken.go": "not go",
+	})
+
+	files := []string{
+		"README.md",
+		"docs/guide.md",
+		"apps/mobile/src/index.ts",
+		"packages/web/src/app.ts",
+		"tools/cmd/lint/lint.go",
+		"crates/parser/src/lib.rs",
+		"internal/render/render.go",
+		"internal/render/x_test.go",
+		"internal/removed/gone.go",
+		"cmd/serve/main.go",
+		"vendor/lib/broken/broken.go",
+		".github/workflows/ci.yml",
+		"docs/api.md",
+		"apps/tablet/main.ts",
+	}
+	rules := []ScopeRule{
+		{Glob: "apps/*/", Scope: "{1}"},
+		{Glob: "vendor/", Scope: "{3}"},
+	}
+
+	got, err := Scopes(root, files, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ScopeSuggestion{
+		{Scope: "docs", Files: []string{"docs/guide.md", "docs/api.md"}, Reason: "directory docs/"},
+		{Scope: "renderer", Files: []string{"internal/render/render.go", "internal/render/x_test.go"}, Reason: "Go package internal/render"},
+		{Scope: "mobile", Files: []string{"apps/mobile/src/index.ts"}, Reason: "matches apps/*/"},
+		{Scope: "web", Files: []string{"packages/web/src/app.ts"}, Reason: "module packages/web/package.json"},
+		{Scope: "tools", Files: []string{"tools/cmd/lint/lint.go"}, Reason: "module tools/go.mod"},
+		{Scope: "parser-core", Files: []string{"crates/parser/src/lib.rs"}, Reason: "module crates/parser/Cargo.toml"},
+		{Scope: "removed", Files: []string{"internal/removed/gone.go"}, Reason: "Go package internal/removed"},
+		{Scope: "serve", Files: []string{"cmd/serve/main.go"}, Reason: "Go package cmd/serve"},
+		{Scope: "broken", Files: []string{"vendor/lib/broken/broken.go"}, Reason: "Go package vendor/lib/broken"},
+		{Scope: "github", Files: []string{".github/workflows/ci.yml"}, Reason: "directory .github/"},
+		{Scope: "tablet", Files: []string{"apps/tablet/main.ts"}, Reason: "matches apps/*/"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Scopes() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestScopesInvalidRule(t *testing.T) {
+	if _, err := Scopes(t.TempDir(), []string{"a/b.go"}, []ScopeRule{{Glob: "", Scope: "x"}}); err == nil {
+		t.Error("Scopes() succeeded with an empty glob, want an error")
+	}
+}
+
+func TestExpandScope(t *testing.T) {
+	tests := []struct {
+		scope    string
+		captures []string
+		want     string
+	}{
+		{"{1}", []string{"api"}, "api"},
+		{"{2}-{1}", []string{"api", "v2/"}, "v2-api"},
+		{"web", []string{"api"}, "web"},
+		{"{3}", []string{"api"}, ""},
+		{"{0}", []string{"api"}, ""},
+	}
+
+	for _, test := range tests {
+		if got := expandScope(test.scope, test.captures); got != test.want {
+			t.Errorf("expandScope(%q, %q) = %q, want %q", test.scope, test.captures, got, test.want)
+		}
+	}
+}
+
+func TestManifestNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		read    func(string) string
+		content string
+		want    string
+	}{
+		{"package.json", packageJSONName, `{"name": "web"}`, "web"},
+		{"package.json", packageJSONName, `{"name": "@acme/web"}`, "web"},
+		{"package.json", packageJSONName, `{"private": true}`, ""},
+		{"package.json", packageJSONName, `{"name": `, ""},
+		{"go.mod", goModuleName, "module example.com/acme/tools\n", "tools"},
+		{"go.mod", goModuleName, "module example.com/acme/tools/v2\n", "tools"},
+		{"go.mod", goModuleName, "// comment\nmodule \"example.com/quoted\"\n", "quoted"},
+		{"go.mod", goModuleName, "module tools\n", "tools"},
+		{"go.mod", goModuleName, "module v2\n", "v2"},
+		{"go.mod", goModuleName, "go 1.24\n", ""},
+		{"Cargo.toml", cargoName, "[package]\nname = \"parser\"\nversion = \"0.1.0\"\n", "parser"},
+		{"Cargo.toml", cargoName, "[workspace]\nmembers = [\"a\"]\n[[bin]]\nname = \"tool\"\n", ""},
+	}
+
+	for _, test := range tests {
+		path := filepath.Join(t.TempDir(), test.name)
+		if err := os.WriteFile(path, []byte(test.content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if got := test.read(path); got != test.want {
+			t.Errorf("%s %q: name = %q, want %q", test.name, test.content, got, test.want)
+		}
+		if got := test.read(path + ".missing"); got != "" {
+			t.Errorf("%s: name of a missing file = %q, want none", test.name, got)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package infer
+
+import (
+	"strings"
+	"unicode"
+)
+
+// TypeRule suggests a commit type when the staged changes meet its conditions:
+// every staged file matches one of Globs, and with WhitespaceOnly the diff only
+// changes whitespace.
+type TypeRule struct {
+	Type string
+	// Reason tells the user why the type is suggested.
+	Reason string
+	// Globs are the patterns every staged file must match; see CompileGlob.
+	Globs []string
+	// WhitespaceOnly requires a diff changing nothing but whitespace.
+	WhitespaceOnly bool
+}
+
+// TypeSuggestion is a commit type proposed for the staged changes.
+type TypeSuggestion struct {
+	Type   string
+	Reason string
+}
+
+// DefaultTypeRules returns the built-in rules, in order of precedence.
+func DefaultTypeRules() []TypeRule {
+	return []TypeRule{
+		{
+			Type:           "style",
+			Reason:         "the diff only changes whitespace",
+			WhitespaceOnly: true,
+		},
+		{
+			Type:   "test",
+			Reason: "only test files are staged",
+			Globs:  []string{"*_test.go", "test/", "tests/", "testdata/", "*.test.js", "*.test.ts", "*.spec.js", "*.spec.ts"},
+		},
+		{
+			Type:   "docs",
+			Reason: "only Markdown files are staged",
+			Globs:  []string{"*.md", "*.mdx"},
+		},
+		{
+			Type:   "ci",
+			Reason: "only CI configuration files are staged",
+			Globs:  []string{".github/workflows/", ".gitlab-ci.yml", ".circleci/", "azure-pipelines.yml", ".travis.yml"},
+		},
+		{
+			Type:   "build",
+			Reason: "only dependency manifests and lockfiles are staged",
+			Globs: []string{
+				"go.mod", "go.sum", "package-lock.json", "yarn.lock", "pnpm-lock.yaml",
+				"Cargo.lock", "Gemfile.lock", "poetry.lock", "composer.lock",
+			},
+		},
+	}
+}
+
+// Type returns the suggestion of the first rule met by the staged files and their
+// unified diff. It reports false when no rule applies.
+func Type(files []string, patch string, rules []TypeRule) (TypeSuggestion, bool, error) {
+	if len(files) == 0 {
+		return TypeSuggestion{}, false, nil
+	}
+
+	for _, rule := range rules {
+		if rule.WhitespaceOnly && !WhitespaceOnly(patch) {
+			continue
+		}
+
+		if len(rule.Globs) > 0 {
+			globs := make([]*Glob, len(rule.Globs))
+			for i, pattern := range rule.Globs {
+				glob, err := CompileGlob(pattern)
+				if err != nil {
+					return TypeSuggestion{}, false, err
+				}
+				globs[i] = glob
+			}
+			if !allMatch(files, globs) {
+				continue
+			}
+		}
+
+		return TypeSuggestion{Type: rule.Type, Reason: rule.Reason}, true, nil
+	}
+
+	return TypeSuggestion{}, false, nil
+}
+
+// allMatch reports whether every file matches one of globs.
+func allMatch(files []string, globs []*Glob) bool {
+	for _, file := range files {
+		matched := false
+		for _, glob := range globs {
+			if _, ok := glob.Match(file); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// WhitespaceOnly reports whether a unified diff changes lines but, in every file,
+// the removed and added text are the same once whitespace is dropped, like
+// `git diff --ignore-all-space` showing nothing. Binary changes never qualify.
+func WhitespaceOnly(patch string) bool {
+	var removed, added strings.Builder
+	changed, equal, inHunk := false, true, false
+
+	// compare checks the file read so far and starts the next one.
+	compare := func() {
+		if removed.String() != added.String() {
+			equal = false
+		}
+		removed.Reset()
+		added.Reset()
+	}
+
+	for _, line := range strings.Split(patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			compare()
+			inHunk = false
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case strings.HasPrefix(line, "Binary files ") || strings.HasPrefix(line, "GIT binary patch"):
+			return false
+		case !inHunk:
+			// File headers such as "--- a/file" are not changes.
+		case strings.HasPrefix(line, "-"):
+			changed = true
+			removed.WriteString(stripSpace(line[1:]))
+		case strings.HasPrefix(line, "+"):
+			changed = true
+			added.WriteString(stripSpace(line[1:]))
+		}
+	}
+	compare()
+
+	return changed && equal
+}
+
+// stripSpace removes every whitespace character from s.
+func stripSpace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,142 @@
+package infer
+
+import "testing"
+
+// whitespacePatch re-indents a line of main.go.
+const whitespacePatch = `diff --git a/main.go b/main.go
+index 0123456..789abcd 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+ func main() {
+-  run()
++	run()
+ }
+`
+
+// contentPatch changes a line of main.go.
+const contentPatch = `diff --git a/main.go b/main.go
+index 0123456..789abcd 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+ func main() {
+-	run()
++	start()
+ }
+`
+
+func TestType(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		patch string
+		want  string
+	}{
+		{name: "nothing staged", want: ""},
+		{name: "whitespace", files: []string{"main.go"}, patch: whitespacePatch, want: "style"},
+		{name: "whitespace in docs", files: []string{"README.md"}, patch: whitespacePatch, want: "style"},
+		{name: "go tests", files: []string{"main_test.go", "internal/x/x_test.go", "internal/x/testdata/in.txt"}, patch: contentPatch, want: "test"},
+		{name: "js specs", files: []string{"src/app.spec.ts", "tests/e2e/login.js"}, patch: contentPatch, want: "test"},
+		{name: "tests and code", files: []string{"main_test.go", "main.go"}, patch: contentPatch, want: ""},
+		{name: "docs", files: []string{"README.md", "docs/guide.mdx"}, patch: contentPatch, want: "docs"},
+		{name: "ci", files: []string{".github/workflows/ci.yml", ".gitlab-ci.yml"}, patch: contentPatch, want: "ci"},
+		{name: "nested workflows", files: []string{"examples/.github/workflows/ci.yml"}, patch: contentPatch, want: ""},
+		{name: "dependencies", files: []string{"go.mod", "go.sum", "web/package-lock.json"}, patch: contentPatch, want: "build"},
+		{name: "package.json", files: []string{"package.json"}, patch: contentPatch, want: ""},
+		{name: "code", files: []string{"main.go"}, patch: contentPatch, want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			suggestion, ok, err := Type(test.files, test.patch, DefaultTypeRules())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != (test.want != "") || suggestion.Type != test.want {
+				t.Errorf("Type() = %q, %v, want %q", suggestion.Type, ok, test.want)
+			}
+			if ok && suggestion.Reason == "" {
+				t.Error("suggestion without a reason")
+			}
+		})
+	}
+}
+
+func TestTypeCustomRules(t *testing.T) {
+	rules := []TypeRule{
+		{Type: "chore", Reason: "only scripts", Globs: []string{"scripts/"}},
+		{Type: "fix", Reason: "always"},
+	}
+
+	if suggestion, _, _ := Type([]string{"scripts/release.sh"}, contentPatch, rules); suggestion.Type != "chore" {
+		t.Errorf("Type(scripts) = %q, want chore", suggestion.Type)
+	}
+	if suggestion, _, _ := Type([]string{"main.go"}, contentPatch, rules); suggestion.Type != "fix" {
+		t.Errorf("Type(main.go) = %q, want the catch-all rule", suggestion.Type)
+	}
+	if _, _, err := Type([]string{"main.go"}, contentPatch, []TypeRule{{Type: "chore", Globs: []string{""}}}); err == nil {
+		t.Error("Type() succeeded with an empty glob, want an error")
+	}
+}
+
+func TestWhitespaceOnly(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		want  bool
+	}{
+		{name: "empty", patch: "", want: false},
+		{name: "indentation", patch: whitespacePatch, want: true},
+		{name: "content", patch: contentPatch, want: false},
+		{
+			name:  "trailing space and blank lines",
+			patch: "diff --git a/a.txt b/a.txt\n--- a/a.txt\n+++ b/a.txt\n@@ -1,2 +1,3 @@\n-one  \n+one\n+\n two\n",
+			want:  true,
+		},
+		{
+			name:  "split line",
+			patch: "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1,2 @@\n-call(a, b)\n+call(a,\n+\tb)\n",
+			want:  true,
+		},
+		{
+			name:  "mode change only",
+			patch: "diff --git a/run.sh b/run.sh\nold mode 100644\nnew mode 100755\n",
+			want:  false,
+		},
+		{
+			name:  "binary",
+			patch: whitespacePatch + "diff --git a/logo.png b/logo.png\nindex 0123456..789abcd 100644\nBinary files a/logo.png and b/logo.png differ\n",
+			want:  false,
+		},
+		{
+			name:  "binary patch",
+			patch: "diff --git a/logo.png b/logo.png\nindex 0123456..789abcd 100644\nGIT binary patch\nliteral 12\nzcmZ?wbhEHb\n",
+			want:  false,
+		},
+		{
+			name:  "two files reindented",
+			patch: whitespacePatch + "diff --git a/b.go b/b.go\n--- a/b.go\n+++ b/b.go\n@@ -1 +1 @@\n-x :=  1\n+x := 1\n",
+			want:  true,
+		},
+		{
+			name: "line moved between files",
+			patch: "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1,2 +1 @@\n keep()\n-moved()\n" +
+				"diff --git a/b.go b/b.go\n--- a/b.go\n+++ b/b.go\n@@ -1 +1,2 @@\n keep()\n+moved()\n",
+			want: false,
+		},
+		{
+			name:  "changed line starting with dashes",
+			patch: "diff --git a/a.md b/a.md\n--- a/a.md\n+++ b/a.md\n@@ -1 +1 @@\n---- rule\n+--- rule  \n",
+			want:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := WhitespaceOnly(test.patch); got != test.want {
+				t.Errorf("WhitespaceOnly() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
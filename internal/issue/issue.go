@@ -0,0 +1,206 @@
+// Package issue recognises issue and merge request references in the syntaxes of
+// the common trackers, and extracts them from branch names.
+package issue
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	t "github.com/GiulianoPoeta99/conventional_commits_cli/internal/types"
+)
+
+// Syntax is a way of writing an issue reference.
+type Syntax struct {
+	// Name identifies the syntax in the configuration.
+	Name string
+	// Example is shown to the user when a reference is invalid.
+	Example string
+	// pattern matches a whole reference; its "id" group is the issue identifier.
+	pattern *regexp.Regexp
+}
+
+// Syntaxes lists the supported syntaxes.
+var Syntaxes = []Syntax{
+	{Name: "github", Example: "#123", pattern: regexp.MustCompile(`^#(?P<id>[0-9]+)$`)},
+	{Name: "github-repo", Example: "owner/repo#123", pattern: regexp.MustCompile(`^[\w.-]+/[\w.-]+#(?P<id>[0-9]+)$`)},
+	{Name: "jira", Example: "PROJ-123", pattern: regexp.MustCompile(`^(?P<id>[A-Z][A-Z0-9]+-[0-9]+)$`)},
+	{Name: "gitlab-mr", Example: "!45", pattern: regexp.MustCompile(`^(?:[\w.-]+(?:/[\w.-]+)+)?!(?P<id>[0-9]+)$`)},
+	{Name: "url", Example: "https://...", pattern: regexp.MustCompile(`^(?P<id>https?://[^\s/]+\S*)$`)},
+}
+
+// Ref is a parsed issue reference.
+type Ref struct {
+	// Text is the reference as written.
+	Text string
+	// Syntax is the name of the syntax it is written in.
+	Syntax string
+	// ID is the issue identifier: the number, the tracker key or the URL.
+	ID string
+}
+
+// Names returns the names of the supported syntaxes.
+func Names() []string {
+	names := []string{}
+	for _, syntax := range Syntaxes {
+		names = append(names, syntax.Name)
+	}
+	return names
+}
+
+// Parse reads a reference written in one of the named syntaxes; nil means any of them.
+func Parse(text string, names []string) (Ref, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return Ref{}, errors.New("issue reference cannot be empty")
+	}
+
+	examples := []string{}
+	for _, syntax := range enabled(names) {
+		if match := syntax.pattern.FindStringSubmatch(text); match != nil {
+			return Ref{Text: text, Syntax: syntax.Name, ID: match[syntax.pattern.SubexpIndex("id")]}, nil
+		}
+		examples = append(examples, syntax.Example)
+	}
+
+	return Ref{}, fmt.Errorf("invalid issue reference %q (expected %s)", text, joinOr(examples))
+}
+
+// enabled returns the syntaxes whose name is listed, or all of them when names is nil.
+func enabled(names []string) []Syntax {
+	if names == nil {
+		return Syntaxes
+	}
+
+	syntaxes := []Syntax{}
+	for _, syntax := range Syntaxes {
+		for _, name := range names {
+			if syntax.Name == name {
+				syntaxes = append(syntaxes, syntax)
+				break
+			}
+		}
+	}
+	return syntaxes
+}
+
+// joinOr joins values as "a, b or c".
+func joinOr(values []string) string {
+	if len(values) < 2 {
+		return strings.Join(values, "")
+	}
+	return strings.Join(values[:len(values)-1], ", ") + " or " + values[len(values)-1]
+}
+
+// BranchPattern extracts issue references from branch names.
+type BranchPattern struct {
+	// Pattern is a regular expression searched in the branch name.
+	Pattern string
+	// Ref builds the reference from a match; "{1}", "{2}", ... stand for its groups.
+	Ref string
+}
+
+// DefaultBranchPatterns returns the built-in patterns, recognising tracker keys
+// anywhere (feature/PROJ-1234-login) and an issue number after a prefix naming an
+// issue or a change (fix/567-crash, issue-89). Other numbers, such as the dates and
+// versions of release/2024-10, are not taken for issues.
+func DefaultBranchPatterns() []BranchPattern {
+	return []BranchPattern{
+		{Pattern: `([A-Z][A-Z0-9]+-[0-9]+)`, Ref: "{1}"},
+		{Pattern: `^(?:issues?|fix|bug|bugfix|hotfix|feat|feature)[-_/]#?([0-9]+)(?:[-_/]|$)`, Ref: "#{1}"},
+	}
+}
+
+// placeholderPattern matches the group references of BranchPattern.Ref.
+var placeholderPattern = regexp.MustCompile(`\{(\d+)\}`)
+
+// CompileBranchPattern checks a branch pattern, returning its compiled expression.
+func CompileBranchPattern(p BranchPattern) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(p.Pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, match := range placeholderPattern.FindAllStringSubmatch(p.Ref, -1) {
+		if n, _ := strconv.Atoi(match[1]); n < 1 || n > re.NumSubexp() {
+			return nil, fmt.Errorf("ref %q refers to group %s, but the pattern has %d", p.Ref, match[1], re.NumSubexp())
+		}
+	}
+	return re, nil
+}
+
+// FromBranch returns the references found in a branch name by patterns, in order and
+// without duplicates, keeping only those written in one of the named syntaxes.
+func FromBranch(branch string, patterns []BranchPattern, names []string) []string {
+	refs := []string{}
+	seen := map[string]bool{}
+
+	for _, p := range patterns {
+		re, err := CompileBranchPattern(p)
+		if err != nil {
+			continue
+		}
+
+		for _, match := range re.FindAllStringSubmatch(branch, -1) {
+			ref := placeholderPattern.ReplaceAllStringFunc(p.Ref, func(placeholder string) string {
+				n, _ := strconv.Atoi(placeholder[1 : len(placeholder)-1])
+				return match[n]
+			})
+			if _, err := Parse(ref, names); err != nil || seen[ref] {
+				continue
+			}
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// DefaultRelation is the relation of references given without one.
+const DefaultRelation = "Refs"
+
+// Relations lists the footer keys linking a commit to an issue, in their default order.
+// The closing keywords make trackers close the issue when the commit is merged.
+var Relations = []string{"Closes", "Fixes", "Resolves", "Part-of", "Refs"}
+
+// RelationDescriptions explains each relation to the user.
+var RelationDescriptions = map[string]string{
+	"Closes":   "closes the issue when merged",
+	"Fixes":    "fixes the bug and closes the issue when merged",
+	"Resolves": "resolves the issue and closes it when merged",
+	"Part-of":  "is one step of the issue",
+	"Refs":     "is related to the issue",
+}
+
+// Relation returns the canonical spelling of a relation, matched case-insensitively.
+func Relation(name string) (string, bool) {
+	for _, relation := range Relations {
+		if strings.EqualFold(relation, name) {
+			return relation, true
+		}
+	}
+	return "", false
+}
+
+// SortReferences orders refs by the position of their relation in order, keeping the
+// order in which they were given within a relation. Relations missing from order
+// come last.
+func SortReferences(refs []t.IssueReference, order []string) []t.IssueReference {
+	rank := func(relation string) int {
+		for i, r := range order {
+			if r == relation {
+				return i
+			}
+		}
+		return len(order)
+	}
+
+	sorted := append([]t.IssueReference{}, refs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return rank(sorted[i].Relation) < rank(sorted[j].Relation)
+	})
+	return sorted
+}
@@ -0,0 +1,31 @@
+package issue
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestFromBranchDefaults checks the references the built-in patterns find in branch
+// names, and that dates and versions are not taken for issue numbers.
+func TestFromBranchDefaults(t *testing.T) {
+	tests := []struct {
+		branch string
+		want   []string
+	}{
+		{"feature/PROJ-1234-login-timeout", []string{"PROJ-1234"}},
+		{"fix/567-crash", []string{"#567"}},
+		{"issue-89", []string{"#89"}},
+		{"feat/12", []string{"#12"}},
+		{"release/2024-10", []string{}},
+		{"release/1.2.3", []string{}},
+		{"2024-10-cleanup", []string{}},
+		{"main", []string{}},
+	}
+
+	for _, test := range tests {
+		got := FromBranch(test.branch, DefaultBranchPatterns(), Names())
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("FromBranch(%q) = %q, want %q", test.branch, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+// Package lint validates commit messages against the Conventional Commits 1.0 grammar
+// and the configured commit types.
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	commit "github.com/GiulianoPoeta99/conventional_commits_cli/internal"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/trailer"
+	t "github.com/GiulianoPoeta99/conventional_commits_cli/internal/types"
+)
+
+// Severity tells whether a diagnostic makes the message invalid.
+type Severity string
+
+const (
+	// SeverityError marks a violation that makes the message invalid.
+	SeverityError Severity = "error"
+	// SeverityWarning marks a violation that is reported but accepted.
+	SeverityWarning Severity = "warning"
+)
+
+// Rule identifiers reported in diagnostics.
+const (
+	RuleMessageEmpty     = "message-empty"
+	RuleHeaderFormat     = "header-format"
+	RuleHeaderMaxLength  = "header-max-length"
+	RuleTypeEnum         = "type-enum"
+	RuleTypeCase         = "type-case"
+	RuleSubjectMinLength = "subject-min-length"
+	RuleSubjectFullStop  = "subject-full-stop"
+	RuleBodyLeadingBlank = "body-leading-blank"
+	RuleTrailerRequired  = "trailer-required"
+	RuleTrailerPattern   = "trailer-pattern"
+)
+
+// DefaultHeaderMaxLength is the header length above which a warning is reported.
+const DefaultHeaderMaxLength = 72
+
+// SubjectMinLength is the minimum description length, shared with the wizard.
+const SubjectMinLength = 3
+
+// ignoredPatterns match the messages generated by git itself, which are not linted.
+var ignoredPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^Merge (branch|branches|pull request|remote-tracking branch|tag|commit) `),
+	regexp.MustCompile(`^Revert "`),
+}
+
+// DefaultCommentChar starts the comment lines when core.commentChar is not set.
+const DefaultCommentChar = "#"
+
+// scissorsMarker follows the comment character on the line below which
+// `git commit --verbose` places the diff.
+const scissorsMarker = " ------------------------ >8 ------------------------"
+
+// Diagnostic is a single rule violation with its position in the message.
+type Diagnostic struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Line     int      `json:"line"`
+	Column   int      `json:"column"`
+	Message  string   `json:"message"`
+}
+
+// String formats the diagnostic as "line:column: severity [rule] message".
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%d:%d: %s [%s] %s", d.Line, d.Column, d.Severity, d.Rule, d.Message)
+}
+
+// Options configures the linter.
+type Options struct {
+	// Types is the catalogue of accepted commit types.
+	Types []t.CommitType
+	// Emojis is the catalogue used to recognise emojis in the description.
+	Emojis []t.Emoji
+	// HeaderMaxLength is the longest accepted header; zero disables the rule.
+	HeaderMaxLength int
+	// Trailers are the trailer rules of the project.
+	Trailers []trailer.Rule
+}
+
+// CleanMessage removes the lines starting with commentChar and everything below the
+// scissors line, as git does before recording a message written in an editor.
+// An empty commentChar stands for DefaultCommentChar.
+func CleanMessage(message, commentChar string) string {
+	if commentChar == "" {
+		commentChar = DefaultCommentChar
+	}
+
+	lines := []string{}
+	for _, line := range strings.Split(strings.ReplaceAll(message, "\r\n", "\n"), "\n") {
+		if line == commentChar+scissorsMarker {
+			break
+		}
+		if strings.HasPrefix(line, commentChar) {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " \t"))
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// IsIgnored reports whether the message was generated by git, or is a transient
+// fixup!, squash! or amend! commit meant to be autosquashed, and should not be linted.
+func IsIgnored(message string) bool {
+	if commit.IsFixup(message) {
+		return true
+	}
+	for _, pattern := range ignoredPatterns {
+		if pattern.MatchString(message) {
+			return true
+		}
+	}
+	return false
+}
+
+// Lint validates a cleaned commit message and returns the violations found.
+func Lint(message string, opts Options) []Diagnostic {
+	diagnostics := []Diagnostic{}
+
+	if strings.TrimSpace(message) == "" {
+		return append(diagnostics, Diagnostic{
+			Rule: RuleMessageEmpty, Severity: SeverityError, Line: 1, Column: 1,
+			Message: "commit message is empty",
+		})
+	}
+	if IsIgnored(message) {
+		return diagnostics
+	}
+
+	config, err := commit.ParseCommitMessage(message, opts.Types, opts.Emojis)
+
+	// Report the grammar errors found by the parser.
+	validHeader := true
+	var parseErrors commit.ParseErrors
+	if errors.As(err, &parseErrors) {
+		for _, parseErr := range parseErrors {
+			rule := RuleHeaderFormat
+			if parseErr.Line > 1 {
+				rule = RuleBodyLeadingBlank
+			} else {
+				validHeader = false
+			}
+			diagnostics = append(diagnostics, Diagnostic{
+				Rule: rule, Severity: SeverityError, Line: parseErr.Line, Column: parseErr.Column,
+				Message: parseErr.Message,
+			})
+		}
+	}
+
+	header, _, _ := strings.Cut(message, "\n")
+
+	// Check the type against the catalogue once the header is well formed.
+	if validHeader {
+		diagnostics = append(diagnostics, lintType(config.Type.Code, opts.Types)...)
+	}
+
+	// Check the description.
+	if config.Description != "" {
+		column := utf8.RuneCountInString(header) - utf8.RuneCountInString(config.Description) + 1
+		if utf8.RuneCountInString(config.Description) < SubjectMinLength {
+			diagnostics = append(diagnostics, Diagnostic{
+				Rule: RuleSubjectMinLength, Severity: SeverityError, Line: 1, Column: column,
+				Message: fmt.Sprintf("description must have at least %d characters", SubjectMinLength),
+			})
+		}
+		if strings.HasSuffix(config.Description, ".") {
+			diagnostics = append(diagnostics, Diagnostic{
+				Rule: RuleSubjectFullStop, Severity: SeverityWarning, Line: 1, Column: utf8.RuneCountInString(header),
+				Message: "description should not end with a full stop",
+			})
+		}
+	}
+
+	// Check the header length.
+	if length := utf8.RuneCountInString(header); opts.HeaderMaxLength > 0 && length > opts.HeaderMaxLength {
+		diagnostics = append(diagnostics, Diagnostic{
+			Rule: RuleHeaderMaxLength, Severity: SeverityWarning, Line: 1, Column: opts.HeaderMaxLength + 1,
+			Message: fmt.Sprintf("header is %d characters long, the maximum is %d", length, opts.HeaderMaxLength),
+		})
+	}
+
+	diagnostics = append(diagnostics, lintTrailers(message, commit.Footers(config), opts.Trailers)...)
+
+	return diagnostics
+}
+
+// lintTrailers checks the trailers of the message against the trailer rules.
+// Missing trailers are reported on the last line.
+func lintTrailers(message string, footers []t.Trailer, rules []trailer.Rule) []Diagnostic {
+	lines := strings.Split(message, "\n")
+	diagnostics := []Diagnostic{}
+
+	for _, problem := range trailer.Check(footers, rules) {
+		if problem.Index < 0 {
+			diagnostics = append(diagnostics, Diagnostic{
+				Rule: RuleTrailerRequired, Severity: SeverityError, Line: len(lines), Column: 1,
+				Message: problem.Message,
+			})
+			continue
+		}
+
+		// Find the line of the offending trailer among those sharing its key.
+		nth := 0
+		for _, footer := range footers[:problem.Index] {
+			if strings.EqualFold(footer.Key, problem.Key) {
+				nth++
+			}
+		}
+		line := len(lines)
+		for i, text := range lines {
+			key, _, found := strings.Cut(text, ":")
+			if found && strings.EqualFold(key, problem.Key) {
+				if nth == 0 {
+					line = i + 1
+					break
+				}
+				nth--
+			}
+		}
+
+		diagnostics = append(diagnostics, Diagnostic{
+			Rule: RuleTrailerPattern, Severity: SeverityError, Line: line, Column: len(problem.Key) + 3,
+			Message: problem.Message,
+		})
+	}
+	return diagnostics
+}
+
+// lintType checks the case of the type and that it belongs to the catalogue.
+func lintType(code string, commitTypes []t.CommitType) []Diagnostic {
+	known := func(code string) bool {
+		for _, commitType := range commitTypes {
+			if commitType.Code == code {
+				return true
+			}
+		}
+		return false
+	}
+
+	if known(code) {
+		return nil
+	}
+	if lower := strings.ToLower(code); lower != code && known(lower) {
+		return []Diagnostic{{
+			Rule: RuleTypeCase, Severity: SeverityError, Line: 1, Column: 1,
+			Message: fmt.Sprintf("type %q must be lowercase (%q)", code, lower),
+		}}
+	}
+
+	codes := []string{}
+	for _, commitType := range commitTypes {
+		codes = append(codes, commitType.Code)
+	}
+	return []Diagnostic{{
+		Rule: RuleTypeEnum, Severity: SeverityError, Line: 1, Column: 1,
+		Message: fmt.Sprintf("unknown type %q, expected one of: %s", code, strings.Join(codes, ", ")),
+	}}
+}
+
+// HasErrors reports whether any diagnostic is an error.
+func HasErrors(diagnostics []Diagnostic) bool {
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	d "github.com/GiulianoPoeta99/conventional_commits_cli/internal/data"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/trailer"
+)
+
+// testOptions returns the built-in catalogues with a ticket trailer rule.
+func testOptions() Options {
+	return Options{
+		Types:           d.GetCommitTypes(),
+		Emojis:          d.GetEmojis(),
+		HeaderMaxLength: DefaultHeaderMaxLength,
+		Trailers:        []trailer.Rule{{Key: "Ticket", Pattern: `^[A-Z]+-\d+$`}},
+	}
+}
+
+func TestLint(tt *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []string
+	}{
+		{name: "valid", message: "feat(api): add the endpoint\n\nBody text.\n\nTicket: API-12"},
+		{name: "empty", message: " \n", want: []string{"1:1: error [message-empty]"}},
+		{name: "no colon", message: "feat add the endpoint", want: []string{"1:5: error [header-format]"}},
+		{name: "unknown type", message: "feature: add the endpoint", want: []string{"1:1: error [type-enum]"}},
+		{name: "uppercase type", message: "Feat: add the endpoint", want: []string{"1:1: error [type-case]"}},
+		{name: "short description", message: "fix: ab", want: []string{"1:6: error [subject-min-length]"}},
+		{name: "full stop", message: "fix: handle the timeout.", want: []string{"1:24: warning [subject-full-stop]"}},
+		{
+			name:    "long header",
+			message: "fix: " + "handle the timeout of the upload requests sent to the storage service now",
+			want:    []string{"1:73: warning [header-max-length]"},
+		},
+		{name: "no blank line", message: "fix: handle the timeout\nBody text.", want: []string{"2:1: error [body-leading-blank]"}},
+		{
+			name:    "trailer pattern",
+			message: "fix: handle the timeout\n\nTicket: API-1\nTicket: api-2",
+			want:    []string{"4:9: error [trailer-pattern]"},
+		},
+		{name: "merge", message: "Merge branch 'main' into feature"},
+		{name: "revert", message: "Revert \"feat: add the endpoint\"\n\nThis reverts commit 0123abc."},
+		{name: "fixup", message: "fixup! feat: add the endpoint"},
+		{name: "squash", message: "squash! feat: add the endpoint\n\nmore"},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			got := []string{}
+			for _, diagnostic := range Lint(test.message, testOptions()) {
+				got = append(got, diagnosticPosition(diagnostic))
+			}
+			want := test.want
+			if want == nil {
+				want = []string{}
+			}
+			if !slices.Equal(got, want) {
+				tt.Errorf("Lint(%q) = %q, want %q", test.message, got, want)
+			}
+		})
+	}
+}
+
+// diagnosticPosition formats a diagnostic without its message.
+func diagnosticPosition(diagnostic Diagnostic) string {
+	return fmt.Sprintf("%d:%d: %s [%s]", diagnostic.Line, diagnostic.Column, diagnostic.Severity, diagnostic.Rule)
+}
+
+func TestLintRequiredTrailer(tt *testing.T) {
+	opts := testOptions()
+	opts.Trailers[0].Required = true
+
+	diagnostics := Lint("fix: handle the timeout\n\nBody text.", opts)
+	if len(diagnostics) != 1 || diagnostics[0].Rule != RuleTrailerRequired || diagnostics[0].Line != 3 {
+		tt.Errorf("Lint() = %v, want one trailer-required error on line 3", diagnostics)
+	}
+	if !HasErrors(diagnostics) {
+		tt.Error("HasErrors() = false, want true")
+	}
+}
+
+func TestLintHeaderMaxLengthDisabled(tt *testing.T) {
+	opts := testOptions()
+	opts.HeaderMaxLength = 0
+
+	message := "fix: " + "handle the timeout of the upload requests sent to the storage service now"
+	if diagnostics := Lint(message, opts); len(diagnostics) != 0 {
+		tt.Errorf("Lint() = %v, want no diagnostics", diagnostics)
+	}
+}
+
+func TestHasErrors(tt *testing.T) {
+	warning := Diagnostic{Rule: RuleSubjectFullStop, Severity: SeverityWarning}
+	if HasErrors([]Diagnostic{warning}) {
+		tt.Error("HasErrors(warning) = true, want false")
+	}
+	if !HasErrors([]Diagnostic{warning, {Rule: RuleTypeEnum, Severity: SeverityError}}) {
+		tt.Error("HasErrors(warning, error) = false, want true")
+	}
+}
+
+func TestIsIgnored(tt *testing.T) {
+	tests := []struct {
+		message string
+		want    bool
+	}{
+		{"Merge branch 'feature'", true},
+		{"Merge pull request #12 from user/feature", true},
+		{"Merge remote-tracking branch 'origin/main'", true},
+		{"Merge tag 'v1.0.0'", true},
+		{"Revert \"fix: handle the timeout\"", true},
+		{"fixup! fix: handle the timeout", true},
+		{"squash! fix: handle the timeout", true},
+		{"amend! fix: handle the timeout", true},
+		{"fix: merge branch settings", false},
+		{"Merged the branches", false},
+		{"revert: undo the timeout", false},
+		{"fixup: handle the timeout", false},
+	}
+
+	for _, test := range tests {
+		if got := IsIgnored(test.message); got != test.want {
+			tt.Errorf("IsIgnored(%q) = %v, want %v", test.message, got, test.want)
+		}
+	}
+}
+
+func TestCleanMessage(tt *testing.T) {
+	tests := []struct {
+		name        string
+		message     string
+		commentChar string
+		want        string
+	}{
+		{
+			name:    "comments",
+			message: "# Please enter the commit message\nfix: handle the timeout  \n\n# On branch main\nBody\n\n",
+			want:    "fix: handle the timeout\n\nBody",
+		},
+		{
+			name:    "scissors",
+			message: "fix: handle the timeout\n\n# ------------------------ >8 ------------------------\ndiff --git a/x b/x\n+added\n",
+			want:    "fix: handle the timeout",
+		},
+		{
+			name:    "carriage returns",
+			message: "fix: handle the timeout\r\n\r\nBody\r\n",
+			want:    "fix: handle the timeout\n\nBody",
+		},
+		{
+			name:        "comment char",
+			message:     "; Please enter the commit message\nfix: handle the timeout\n\n#123 is fixed\n",
+			commentChar: ";",
+			want:        "fix: handle the timeout\n\n#123 is fixed",
+		},
+		{
+			name:        "comment char scissors",
+			message:     "fix: handle the timeout\n; ------------------------ >8 ------------------------\ndiff\n",
+			commentChar: ";",
+			want:        "fix: handle the timeout",
+		},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			if got := CleanMessage(test.message, test.commentChar); got != test.want {
+				tt.Errorf("CleanMessage() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
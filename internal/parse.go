@@ -0,0 +1,296 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/issue"
+	t "github.com/GiulianoPoeta99/conventional_commits_cli/internal/types"
+)
+
+// footerPattern matches a footer line: a token followed by ": " or " #".
+// "BREAKING CHANGE" is the only token allowed to contain a space.
+var footerPattern = regexp.MustCompile(`^(BREAKING CHANGE|[A-Za-z0-9][A-Za-z0-9-]*)(: | #)(.*)$`)
+
+// shortcodePattern matches an emoji shortcode such as ":sparkles:".
+var shortcodePattern = regexp.MustCompile(`^:([a-z0-9_+-]+):`)
+
+// ParseError describes a problem found while parsing a commit message.
+// Line and Column are 1-based; Column counts characters, not bytes.
+type ParseError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+// Error formats the error as "line:column: message".
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// ParseErrors collects every problem found in a commit message.
+type ParseErrors []*ParseError
+
+// Error joins the messages of all the errors.
+func (e ParseErrors) Error() string {
+	messages := []string{}
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// ParseCommitMessage is the inverse of FormatCommitMessage: it reads a Conventional Commit
+// message and returns its configuration. Types and emojis are resolved against the given
+// catalogues; unknown ones are kept with their code only.
+// When the message is malformed, the fields that could be read are returned together with
+// a ParseErrors value.
+func ParseCommitMessage(message string, commitTypes []t.CommitType, emojis []t.Emoji) (t.CommitConfig, error) {
+	config := t.CommitConfig{}
+	errs := ParseErrors{}
+
+	message = strings.ReplaceAll(message, "\r\n", "\n")
+	message = strings.TrimRight(message, " \t\n")
+	lines := strings.Split(message, "\n")
+
+	// Parse the header line.
+	errs = append(errs, parseHeader(lines[0], &config, commitTypes, emojis)...)
+
+	// The body and footers must be separated from the header by a blank line.
+	rest := lines[1:]
+	if len(rest) > 0 && strings.TrimSpace(rest[0]) != "" {
+		errs = append(errs, &ParseError{Line: 2, Column: 1, Message: "header must be followed by a blank line"})
+	}
+
+	// Split the remaining lines into body and footer paragraphs.
+	bodyEnd := footerStart(rest)
+	config.Body = strings.Trim(strings.Join(rest[:bodyEnd], "\n"), "\n")
+
+	for _, footer := range parseFooters(rest[bodyEnd:]) {
+		switch footer.Key {
+		case "BREAKING CHANGE", "BREAKING-CHANGE":
+			config.Breaking = true
+			if footer.Value != DefaultBreakingReason {
+				config.BreakingReason = footer.Value
+			}
+		default:
+			// People are recognised whatever their case, e.g. GitHub writes Co-authored-by
+			// and other tools Co-Authored-By.
+			if people := peopleList(&config, footer.Key); people != nil {
+				*people = append(*people, footer.Value)
+				continue
+			}
+			// Issue relations are recognised whatever their case, e.g. "closes #12".
+			if relation, ok := issue.Relation(footer.Key); ok {
+				config.ReferenceIssues = append(config.ReferenceIssues, t.IssueReference{Relation: relation, Issue: footer.Value})
+			} else {
+				config.Trailers = append(config.Trailers, footer)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return config, errs
+	}
+	return config, nil
+}
+
+// peopleList returns the list of config crediting people under key, ignoring case,
+// or nil when key does not credit people.
+func peopleList(config *t.CommitConfig, key string) *[]string {
+	for _, people := range People(config) {
+		if strings.EqualFold(people.Key, key) {
+			return people.Values
+		}
+	}
+	return nil
+}
+
+// parseHeader reads "type(scope)!: emoji description" into config.
+func parseHeader(header string, config *t.CommitConfig, commitTypes []t.CommitType, emojis []t.Emoji) ParseErrors {
+	errs := ParseErrors{}
+	column := func(offset int) int {
+		return utf8.RuneCountInString(header[:offset]) + 1
+	}
+
+	if strings.TrimSpace(header) == "" {
+		return append(errs, &ParseError{Line: 1, Column: 1, Message: "header is empty"})
+	}
+
+	// Read the type.
+	pos := 0
+	for pos < len(header) && isTypeChar(header[pos]) {
+		pos++
+	}
+	if pos == 0 {
+		return append(errs, &ParseError{Line: 1, Column: 1, Message: "header must start with a commit type"})
+	}
+	config.Type = resolveType(header[:pos], commitTypes)
+
+	// Read the optional scope.
+	if pos < len(header) && header[pos] == '(' {
+		end := strings.IndexByte(header[pos:], ')')
+		if end < 0 {
+			return append(errs, &ParseError{Line: 1, Column: column(pos), Message: "scope is missing its closing parenthesis"})
+		}
+		config.Scope = header[pos+1 : pos+end]
+		if strings.TrimSpace(config.Scope) == "" {
+			errs = append(errs, &ParseError{Line: 1, Column: column(pos + 1), Message: "scope must not be empty"})
+		}
+		pos += end + 1
+	}
+
+	// Read the optional breaking change marker.
+	if pos < len(header) && header[pos] == '!' {
+		config.Breaking = true
+		pos++
+	}
+
+	// Read the ": " separator.
+	if pos >= len(header) || header[pos] != ':' {
+		return append(errs, &ParseError{Line: 1, Column: column(pos), Message: "expected ':' after the commit type"})
+	}
+	pos++
+	if pos >= len(header) || header[pos] != ' ' {
+		return append(errs, &ParseError{Line: 1, Column: column(pos), Message: "expected a space after ':'"})
+	}
+	pos++
+
+	// Read the optional emoji, as a shortcode or a symbol.
+	description := header[pos:]
+	if match := shortcodePattern.FindStringSubmatch(description); match != nil {
+		config.Emoji = resolveEmoji(match[1], emojis)
+		description = strings.TrimLeft(description[len(match[0]):], " ")
+	} else if emoji, length := matchEmojiSymbol(description, emojis); length > 0 {
+		config.Emoji = emoji
+		description = strings.TrimLeft(description[length:], " ")
+	}
+	pos = len(header) - len(description)
+
+	config.Description = strings.TrimRight(description, " ")
+	if config.Description == "" {
+		errs = append(errs, &ParseError{Line: 1, Column: column(pos), Message: "description must not be empty"})
+	}
+
+	return errs
+}
+
+// isTypeChar reports whether c can appear in a commit type.
+func isTypeChar(c byte) bool {
+	return c == '-' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// resolveType looks the type code up in the catalogue to fill its description.
+func resolveType(code string, commitTypes []t.CommitType) t.CommitType {
+	for _, commitType := range commitTypes {
+		if commitType.Code == code {
+			return commitType
+		}
+	}
+	return t.CommitType{Code: code}
+}
+
+// resolveEmoji looks the emoji code up in the catalogue to fill its symbol and description.
+func resolveEmoji(code string, emojis []t.Emoji) t.Emoji {
+	for _, emoji := range emojis {
+		if emoji.Code == code {
+			return emoji
+		}
+	}
+	return t.Emoji{Code: code}
+}
+
+// matchEmojiSymbol returns the catalogue emoji whose symbol starts text and the number
+// of bytes it spans, including an optional variation selector.
+func matchEmojiSymbol(text string, emojis []t.Emoji) (t.Emoji, int) {
+	for _, emoji := range emojis {
+		symbol := strings.TrimSuffix(emoji.Symbol, "\uFE0F")
+		if symbol == "" || !strings.HasPrefix(text, symbol) {
+			continue
+		}
+		length := len(symbol)
+		if strings.HasPrefix(text[length:], "\uFE0F") {
+			length += len("\uFE0F")
+		}
+		return emoji, length
+	}
+	return t.Emoji{}, 0
+}
+
+// isFooterLine reports whether line starts a footer.
+func isFooterLine(line string) bool {
+	return footerPattern.MatchString(line)
+}
+
+// footerStart returns the index of the first line of the footers. Like git, only the
+// last paragraph is read as the trailer block, when its first line is a footer line;
+// the BREAKING CHANGE paragraph written by FormatCommitMessage before the trailer block
+// is a footer as well. Earlier paragraphs belong to the body even when they start like
+// a footer, e.g. "Note: ...".
+func footerStart(lines []string) int {
+	begin, end := lastParagraph(lines)
+	if begin == end || !isFooterLine(lines[begin]) {
+		return len(lines)
+	}
+	if isBreakingLine(lines[begin]) {
+		return begin
+	}
+
+	// The BREAKING CHANGE paragraph comes before the trailer block.
+	if before, end := lastParagraph(lines[:begin]); before < end && isBreakingLine(lines[before]) {
+		return before
+	}
+	return begin
+}
+
+// isBreakingLine reports whether line starts the BREAKING CHANGE footer.
+func isBreakingLine(line string) bool {
+	return strings.HasPrefix(line, "BREAKING CHANGE: ") || strings.HasPrefix(line, "BREAKING-CHANGE: ")
+}
+
+// lastParagraph returns the bounds of the last paragraph of lines, ignoring trailing
+// blank lines. They are equal when lines has no paragraph.
+func lastParagraph(lines []string) (int, int) {
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	begin := end
+	for begin > 0 && strings.TrimSpace(lines[begin-1]) != "" {
+		begin--
+	}
+	return begin, end
+}
+
+// parseFooters reads the footer paragraphs into trailers.
+// Indented continuation lines are folded into the previous value with a single space, as
+// git does; other lines are kept on their own line, as Conventional Commits allows.
+func parseFooters(lines []string) []t.Trailer {
+	footers := []t.Trailer{}
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if match := footerPattern.FindStringSubmatch(line); match != nil {
+			value := match[3]
+			if match[2] == " #" {
+				value = "#" + value
+			}
+			footers = append(footers, t.Trailer{Key: match[1], Value: value})
+			continue
+		}
+
+		last := &footers[len(footers)-1]
+		if line[0] == ' ' || line[0] == '\t' {
+			last.Value += " " + strings.TrimSpace(line)
+		} else {
+			last.Value += "\n" + line
+		}
+	}
+
+	return footers
+}
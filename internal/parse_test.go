@@ -0,0 +1,178 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	t "github.com/GiulianoPoeta99/conventional_commits_cli/internal/types"
+)
+
+var (
+	testTypes  = []t.CommitType{{Code: "feat", Description: "A new feature"}, {Code: "fix", Description: "A bug fix"}}
+	testEmojis = []t.Emoji{{Code: "sparkles", Symbol: "✨", Description: "Introduce new features"}}
+)
+
+// TestParseCommitMessageRoundTrip checks that parsing a formatted message gives back
+// its configuration, over every combination of body, breaking change, people, issue
+// references and trailers.
+func TestParseCommitMessageRoundTrip(tt *testing.T) {
+	bodies := []string{
+		"",
+		"Explain the change.",
+		"First paragraph.\n\nSecond paragraph\nover two lines.",
+		"Note: this is the body paragraph.",
+		"Steps:\n\n- one\n- two",
+	}
+	breakings := []struct {
+		breaking bool
+		reason   string
+	}{
+		{false, ""},
+		{true, ""},
+		{true, "the API now returns pages"},
+	}
+	people := []func(*t.CommitConfig){
+		func(*t.CommitConfig) {},
+		func(c *t.CommitConfig) { c.Reviewers = []string{"Jane Doe <jane@example.com>"} },
+		func(c *t.CommitConfig) {
+			c.AckedBy = []string{"Ann <ann@example.com>"}
+			c.TestedBy = []string{"Tom <tom@example.com>"}
+			c.ReportedBy = []string{"Ray <ray@example.com>"}
+			c.CoAuthors = []string{"Bob <bob@example.com>", "Eve <eve@example.com>"}
+		},
+	}
+	refs := [][]t.IssueReference{
+		nil,
+		{{Relation: "Refs", Issue: "#12"}},
+		{{Relation: "Closes", Issue: "#1"}, {Relation: "Refs", Issue: "PROJ-7"}},
+	}
+	trailers := [][]t.Trailer{
+		nil,
+		{{Key: "Signed-off-by", Value: "Jane Doe <jane@example.com>"}},
+		{{Key: "Change-Id", Value: "I0123"}, {Key: "X-Note", Value: "kept as is"}},
+	}
+
+	for _, body := range bodies {
+		for _, breaking := range breakings {
+			for _, credit := range people {
+				for _, ref := range refs {
+					for _, trailer := range trailers {
+						want := t.CommitConfig{
+							Type:            testTypes[0],
+							Scope:           "api",
+							Emoji:           testEmojis[0],
+							Description:     "add pagination",
+							Body:            body,
+							Breaking:        breaking.breaking,
+							BreakingReason:  breaking.reason,
+							ReferenceIssues: ref,
+							Trailers:        trailer,
+						}
+						credit(&want)
+
+						// Like git, a last paragraph shaped like a trailer is read as one
+						// when nothing follows it.
+						if len(Footers(want)) == 0 && !want.Breaking && isFooterLine(body) {
+							continue
+						}
+
+						message := FormatCommitMessage(want)
+						got, err := ParseCommitMessage(message, testTypes, testEmojis)
+						if err != nil {
+							tt.Fatalf("ParseCommitMessage(%q): %v", message, err)
+						}
+						if !reflect.DeepEqual(got, want) {
+							tt.Errorf("ParseCommitMessage(%q)\n got %#v\nwant %#v", message, got, want)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestParseCommitMessageFooters checks which paragraphs are read as footers.
+func TestParseCommitMessageFooters(tt *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		body     string
+		trailers []t.Trailer
+		refs     []t.IssueReference
+		breaking bool
+	}{
+		{
+			name:    "body paragraph shaped like a trailer",
+			message: "feat: x\n\nNote: this is the body paragraph.\n\nRefs: #12",
+			body:    "Note: this is the body paragraph.",
+			refs:    []t.IssueReference{{Relation: "Refs", Issue: "#12"}},
+		},
+		{
+			name:     "last paragraph only",
+			message:  "feat: x\n\nKey: first\n\nOther: second",
+			body:     "Key: first",
+			trailers: []t.Trailer{{Key: "Other", Value: "second"}},
+		},
+		{
+			name:     "breaking change before the trailer block",
+			message:  "feat: x\n\nBody.\n\nBREAKING CHANGE: gone\n\nX-Y: z",
+			body:     "Body.",
+			trailers: []t.Trailer{{Key: "X-Y", Value: "z"}},
+			breaking: true,
+		},
+		{
+			name:     "folded continuation line",
+			message:  "feat: x\n\nX-Long: one\n two",
+			trailers: []t.Trailer{{Key: "X-Long", Value: "one two"}},
+		},
+		{
+			name:    "no footers",
+			message: "feat: x\n\nJust a body.",
+			body:    "Just a body.",
+		},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			got, err := ParseCommitMessage(test.message, testTypes, testEmojis)
+			if err != nil {
+				tt.Fatal(err)
+			}
+			if got.Body != test.body {
+				tt.Errorf("body = %q, want %q", got.Body, test.body)
+			}
+			if !reflect.DeepEqual(got.Trailers, test.trailers) {
+				tt.Errorf("trailers = %#v, want %#v", got.Trailers, test.trailers)
+			}
+			if !reflect.DeepEqual(got.ReferenceIssues, test.refs) {
+				tt.Errorf("references = %#v, want %#v", got.ReferenceIssues, test.refs)
+			}
+			if got.Breaking != test.breaking {
+				tt.Errorf("breaking = %v, want %v", got.Breaking, test.breaking)
+			}
+		})
+	}
+}
+
+// TestParseCommitMessageRoundTripWrapped checks that wrapped messages, whose footers
+// continue on indented lines, are formatted back identically.
+func TestParseCommitMessageRoundTripWrapped(tt *testing.T) {
+	config := t.CommitConfig{
+		Type:           testTypes[1],
+		Description:    "stop the crash",
+		Body:           strings.Repeat("A long sentence that needs wrapping. ", 6),
+		Breaking:       true,
+		BreakingReason: strings.Repeat("the reason is long as well ", 5),
+		Trailers:       []t.Trailer{{Key: "X-Note", Value: strings.Repeat("word ", 30)}},
+	}
+	message := FormatCommitMessage(WrapCommitConfig(config, 72))
+
+	got, err := ParseCommitMessage(message, testTypes, testEmojis)
+	if err != nil {
+		tt.Fatal(err)
+	}
+	if again := FormatCommitMessage(WrapCommitConfig(got, 72)); again != message {
+		tt.Errorf("formatting the parsed message changed it:\n%s\n---\n%s", message, again)
+	}
+}
@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/git"
+	"github.com/GiulianoPoeta99/conventional_commits_cli/internal/issue"
+	t "github.com/GiulianoPoeta99/conventional_commits_cli/internal/types"
+)
+
+// RevertType is the code of the commit type of reverts.
+const RevertType = "revert"
+
+// RevertConfig returns the configuration of a commit reverting commits, given newest
+// first. The description repeats the header of the reverted commit, or of the newest
+// one followed by the count of the others, and the body names each reverted commit
+// the way `git revert` does. The issue references of the reverted commits are carried
+// over as Refs, since reverting does not close or fix them again.
+func RevertConfig(revertType t.CommitType, commits []git.Commit, commitTypes []t.CommitType, emojis []t.Emoji) t.CommitConfig {
+	config := t.CommitConfig{Type: revertType}
+	if len(commits) == 0 {
+		return config
+	}
+
+	config.Description = commits[0].Header()
+	if len(commits) > 1 {
+		config.Description += fmt.Sprintf(" and %d more", len(commits)-1)
+	}
+
+	lines := []string{}
+	seen := map[string]bool{}
+	for _, c := range commits {
+		lines = append(lines, fmt.Sprintf("This reverts commit %s.", c.Hash))
+
+		// Messages that are not Conventional Commits may still have references.
+		reverted, _ := ParseCommitMessage(c.Message, commitTypes, emojis)
+		for _, ref := range reverted.ReferenceIssues {
+			if key := strings.ToLower(ref.Issue); !seen[key] {
+				seen[key] = true
+				config.ReferenceIssues = append(config.ReferenceIssues, t.IssueReference{Relation: issue.DefaultRelation, Issue: ref.Issue})
+			}
+		}
+	}
+	config.Body = strings.Join(lines, "\n")
+	return config
+}
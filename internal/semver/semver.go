@@ -0,0 +1,198 @@
+// Package semver parses, compares and increments semantic version tags.
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// versionPattern matches "MAJOR.MINOR.PATCH[-PRERELEASE][+BUILD]".
+var versionPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// Level is the size of a version increment.
+type Level int
+
+const (
+	// None means no release is needed.
+	None Level = iota
+	// Patch increments the patch number.
+	Patch
+	// Minor increments the minor number and resets the patch number.
+	Minor
+	// Major increments the major number and resets the others.
+	Major
+)
+
+// String returns the lowercase name of the level.
+func (l Level) String() string {
+	switch l {
+	case Patch:
+		return "patch"
+	case Minor:
+		return "minor"
+	case Major:
+		return "major"
+	default:
+		return "none"
+	}
+}
+
+// ParseLevel converts "none", "patch", "minor" or "major" into a Level.
+func ParseLevel(name string) (Level, error) {
+	for _, level := range []Level{None, Patch, Minor, Major} {
+		if level.String() == name {
+			return level, nil
+		}
+	}
+	return None, fmt.Errorf("unknown level %q (expected none, patch, minor or major)", name)
+}
+
+// Version is a semantic version carried by a tag such as "api/v1.2.3-rc.1".
+type Version struct {
+	// Prefix is the part of the tag before the version number, e.g. "v" or "api/v".
+	Prefix     string
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+// Parse reads a tag made of prefix followed by a semantic version.
+// A "v" between the prefix and the number is accepted and kept in Prefix.
+func Parse(tag, prefix string) (Version, error) {
+	if !strings.HasPrefix(tag, prefix) {
+		return Version{}, fmt.Errorf("tag %q does not start with %q", tag, prefix)
+	}
+	rest := strings.TrimPrefix(tag, prefix)
+	if !strings.HasSuffix(prefix, "v") && strings.HasPrefix(rest, "v") {
+		prefix += "v"
+		rest = rest[1:]
+	}
+
+	match := versionPattern.FindStringSubmatch(rest)
+	if match == nil {
+		return Version{}, fmt.Errorf("tag %q is not a semantic version", tag)
+	}
+
+	version := Version{Prefix: prefix, Prerelease: match[4]}
+	version.Major, _ = strconv.Atoi(match[1])
+	version.Minor, _ = strconv.Atoi(match[2])
+	version.Patch, _ = strconv.Atoi(match[3])
+	return version, nil
+}
+
+// Zero returns the version 0.0.0 of the tags with prefix, the starting point before the
+// first release. Like in Parse, a "v" follows the prefix unless it already ends with one.
+func Zero(prefix string) Version {
+	if !strings.HasSuffix(prefix, "v") {
+		prefix += "v"
+	}
+	return Version{Prefix: prefix}
+}
+
+// String returns the tag name of the version.
+func (v Version) String() string {
+	s := fmt.Sprintf("%s%d.%d.%d", v.Prefix, v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// IsPrerelease reports whether the version has a pre-release suffix.
+func (v Version) IsPrerelease() bool {
+	return v.Prerelease != ""
+}
+
+// Bump returns the release version obtained by incrementing v by level.
+// The pre-release suffix is dropped.
+func (v Version) Bump(level Level) Version {
+	next := Version{Prefix: v.Prefix, Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	switch level {
+	case Major:
+		next.Major, next.Minor, next.Patch = v.Major+1, 0, 0
+	case Minor:
+		next.Minor, next.Patch = v.Minor+1, 0
+	case Patch:
+		next.Patch = v.Patch + 1
+	}
+	return next
+}
+
+// Channel returns the pre-release channel ("rc" in "rc.2") and its number,
+// or -1 when the pre-release does not follow the "channel.N" form.
+func (v Version) Channel() (string, int) {
+	channel, number, found := strings.Cut(v.Prerelease, ".")
+	if !found {
+		return v.Prerelease, -1
+	}
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return v.Prerelease, -1
+	}
+	return channel, n
+}
+
+// SameRelease reports whether both versions share the major, minor and patch numbers.
+func (v Version) SameRelease(other Version) bool {
+	return v.Major == other.Major && v.Minor == other.Minor && v.Patch == other.Patch
+}
+
+// Compare returns -1, 0 or +1 following the semantic versioning precedence rules.
+func Compare(a, b Version) int {
+	for _, pair := range [][2]int{{a.Major, b.Major}, {a.Minor, b.Minor}, {a.Patch, b.Patch}} {
+		if pair[0] != pair[1] {
+			return sign(pair[0] - pair[1])
+		}
+	}
+
+	// A release has higher precedence than its pre-releases.
+	switch {
+	case a.Prerelease == b.Prerelease:
+		return 0
+	case a.Prerelease == "":
+		return 1
+	case b.Prerelease == "":
+		return -1
+	}
+
+	aFields := strings.Split(a.Prerelease, ".")
+	bFields := strings.Split(b.Prerelease, ".")
+	for i := 0; i < len(aFields) && i < len(bFields); i++ {
+		if c := compareIdentifier(aFields[i], bFields[i]); c != 0 {
+			return c
+		}
+	}
+	return sign(len(aFields) - len(bFields))
+}
+
+// compareIdentifier compares pre-release identifiers: numbers numerically and below
+// alphanumeric identifiers, which compare in ASCII order.
+func compareIdentifier(a, b string) int {
+	aNumber, aErr := strconv.Atoi(a)
+	bNumber, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return sign(aNumber - bNumber)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// sign returns -1, 0 or +1 according to the sign of n.
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,102 @@
+package semver
+
+import "testing"
+
+// TestParse checks the prefixes accepted before the version number.
+func TestParse(t *testing.T) {
+	tests := []struct {
+		tag, prefix string
+		want        Version
+		err         bool
+	}{
+		{tag: "v1.2.3", want: Version{Prefix: "v", Major: 1, Minor: 2, Patch: 3}},
+		{tag: "1.2.3", want: Version{Major: 1, Minor: 2, Patch: 3}},
+		{tag: "v1.2.3-rc.1+build.5", want: Version{Prefix: "v", Major: 1, Minor: 2, Patch: 3, Prerelease: "rc.1"}},
+		{tag: "api/v1.2.3", prefix: "api/", want: Version{Prefix: "api/v", Major: 1, Minor: 2, Patch: 3}},
+		{tag: "api/v1.2.3", prefix: "api/v", want: Version{Prefix: "api/v", Major: 1, Minor: 2, Patch: 3}},
+		{tag: "api/1.2.3", prefix: "api/", want: Version{Prefix: "api/", Major: 1, Minor: 2, Patch: 3}},
+		{tag: "web/v1.2.3", prefix: "api/", err: true},
+		{tag: "api/vv1.2.3", prefix: "api/v", err: true},
+		{tag: "v1.2", err: true},
+		{tag: "v01.2.3", err: true},
+	}
+
+	for _, test := range tests {
+		got, err := Parse(test.tag, test.prefix)
+		if (err != nil) != test.err {
+			t.Errorf("Parse(%q, %q) error = %v, want error %v", test.tag, test.prefix, err, test.err)
+			continue
+		}
+		if !test.err && got != test.want {
+			t.Errorf("Parse(%q, %q) = %+v, want %+v", test.tag, test.prefix, got, test.want)
+		}
+	}
+}
+
+// TestZero checks that a single "v" follows the prefix of the first version.
+func TestZero(t *testing.T) {
+	for prefix, want := range map[string]string{"": "v0.0.0", "v": "v0.0.0", "api/": "api/v0.0.0", "api/v": "api/v0.0.0"} {
+		if got := Zero(prefix).String(); got != want {
+			t.Errorf("Zero(%q) = %q, want %q", prefix, got, want)
+		}
+		if got := Zero(prefix).Bump(Minor).String(); got != want[:len(want)-5]+"0.1.0" {
+			t.Errorf("Zero(%q).Bump(Minor) = %q", prefix, got)
+		}
+	}
+}
+
+// TestCompare checks the precedence example of the semantic versioning specification,
+// where each version is lower than the next one.
+func TestCompare(t *testing.T) {
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2",
+		"1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "1.10.0", "2.0.0-rc.1", "2.0.0",
+	}
+	versions := []Version{}
+	for _, tag := range ordered {
+		version, err := Parse(tag, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		versions = append(versions, version)
+	}
+
+	for i, a := range versions {
+		for j, b := range versions {
+			want := sign(i - j)
+			if got := Compare(a, b); got != want {
+				t.Errorf("Compare(%s, %s) = %d, want %d", a, b, got, want)
+			}
+		}
+	}
+}
+
+// TestBump checks the increments, which drop the pre-release suffix.
+func TestBump(t *testing.T) {
+	v := Version{Prefix: "v", Major: 1, Minor: 2, Patch: 3, Prerelease: "rc.1"}
+	for level, want := range map[Level]string{None: "v1.2.3", Patch: "v1.2.4", Minor: "v1.3.0", Major: "v2.0.0"} {
+		if got := v.Bump(level).String(); got != want {
+			t.Errorf("Bump(%s) = %q, want %q", level, got, want)
+		}
+	}
+}
+
+// TestChannel checks how pre-releases split into a channel and a number.
+func TestChannel(t *testing.T) {
+	tests := []struct {
+		prerelease string
+		channel    string
+		number     int
+	}{
+		{"rc.2", "rc", 2},
+		{"beta.10", "beta", 10},
+		{"beta", "beta", -1},
+		{"alpha.x", "alpha.x", -1},
+	}
+	for _, test := range tests {
+		channel, number := Version{Prerelease: test.prerelease}.Channel()
+		if channel != test.channel || number != test.number {
+			t.Errorf("Channel(%q) = %q, %d, want %q, %d", test.prerelease, channel, number, test.channel, test.number)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Package trailer checks git trailers against the rules declared by the project and
+// formats them the way `git interpret-trailers` reads them back.
+package trailer
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	t "github.com/GiulianoPoeta99/conventional_commits_cli/internal/types"
+)
+
+// keyPattern matches the trailer keys git recognises: letters, digits and hyphens.
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*$`)
+
+// Rule declares a trailer expected by the project.
+type Rule struct {
+	Key string
+	// Description tells the user what the value is.
+	Description string
+	// Pattern is a regular expression every value must match; empty accepts any value.
+	Pattern string
+	// Required makes messages without the trailer invalid.
+	Required bool
+}
+
+// Problem is a trailer breaking a rule.
+type Problem struct {
+	Key string
+	// Index is the position of the offending trailer, or -1 when it is missing.
+	Index   int
+	Message string
+}
+
+// ValidKey rejects keys that git would not read as trailer keys.
+func ValidKey(key string) error {
+	if key == "" {
+		return errors.New("trailer key cannot be empty")
+	}
+	if !keyPattern.MatchString(key) {
+		return fmt.Errorf("invalid trailer key %q (expected letters, digits and hyphens)", key)
+	}
+	return nil
+}
+
+// Split reads a trailer written as "Key: value" or "Key=value", as `git commit --trailer`
+// accepts them.
+func Split(text string) (t.Trailer, error) {
+	i := strings.IndexAny(text, ":=")
+	if i < 0 {
+		return t.Trailer{}, fmt.Errorf("invalid trailer %q (expected Key=value or Key: value)", text)
+	}
+
+	trailer := t.Trailer{Key: strings.TrimSpace(text[:i]), Value: strings.TrimSpace(text[i+1:])}
+	if err := ValidKey(trailer.Key); err != nil {
+		return t.Trailer{}, err
+	}
+	if trailer.Value == "" {
+		return t.Trailer{}, fmt.Errorf("trailer %s has no value", trailer.Key)
+	}
+	return trailer, nil
+}
+
+// Fold returns a value ready to follow "Key: " in a trailer block: every line after
+// the first is indented by one space and blank lines are dropped, since git reads an
+// unindented line as the end of the trailers and a blank line as the end of the block.
+// `git interpret-trailers --parse` unfolds it by joining the lines with single spaces.
+func Fold(value string) string {
+	lines := []string{}
+	for _, line := range strings.Split(value, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n ")
+}
+
+// Find returns the rule of key, matched case-insensitively like git does.
+func Find(rules []Rule, key string) (Rule, bool) {
+	for _, rule := range rules {
+		if strings.EqualFold(rule.Key, key) {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Validate checks a value against the pattern of the rule.
+func (r Rule) Validate(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("%s cannot be empty", r.Key)
+	}
+	if r.Pattern == "" {
+		return nil
+	}
+
+	re, err := regexp.Compile(r.Pattern)
+	if err != nil {
+		return err
+	}
+	if !re.MatchString(value) {
+		return fmt.Errorf("%s %q does not match %s", r.Key, value, r.Pattern)
+	}
+	return nil
+}
+
+// Check returns the problems of trailers against rules: the values not matching the
+// pattern of their key, then the required keys missing.
+func Check(trailers []t.Trailer, rules []Rule) []Problem {
+	problems := []Problem{}
+
+	for i, trailer := range trailers {
+		if rule, ok := Find(rules, trailer.Key); ok {
+			if err := rule.Validate(trailer.Value); err != nil {
+				problems = append(problems, Problem{Key: trailer.Key, Index: i, Message: err.Error()})
+			}
+		}
+	}
+
+	for _, rule := range rules {
+		if rule.Required && !Has(trailers, rule.Key) {
+			problems = append(problems, Problem{Key: rule.Key, Index: -1, Message: fmt.Sprintf("missing required trailer %s", rule.Key)})
+		}
+	}
+	return problems
+}
+
+// Has reports whether trailers hold one with key, matched case-insensitively.
+func Has(trailers []t.Trailer, key string) bool {
+	for _, trailer := range trailers {
+		if strings.EqualFold(trailer.Key, key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package trailer
+
+import (
+	"reflect"
+	"testing"
+
+	t "github.com/GiulianoPoeta99/conventional_commits_cli/internal/types"
+)
+
+// TestSplit checks both spellings accepted by `git commit --trailer`.
+func TestSplit(tt *testing.T) {
+	tests := []struct {
+		text string
+		want t.Trailer
+		err  bool
+	}{
+		{text: "Key=value", want: t.Trailer{Key: "Key", Value: "value"}},
+		{text: "Key: value", want: t.Trailer{Key: "Key", Value: "value"}},
+		{text: " Change-Id = I0123 ", want: t.Trailer{Key: "Change-Id", Value: "I0123"}},
+		{text: "Link: https://example.com/a=b", want: t.Trailer{Key: "Link", Value: "https://example.com/a=b"}},
+		{text: "Key=a: b", want: t.Trailer{Key: "Key", Value: "a: b"}},
+		{text: "no separator", err: true},
+		{text: "Bad key: value", err: true},
+		{text: "=value", err: true},
+		{text: "Key:", err: true},
+	}
+
+	for _, test := range tests {
+		got, err := Split(test.text)
+		if (err != nil) != test.err {
+			tt.Errorf("Split(%q) error = %v, want error %v", test.text, err, test.err)
+			continue
+		}
+		if got != test.want {
+			tt.Errorf("Split(%q) = %+v, want %+v", test.text, got, test.want)
+		}
+	}
+}
+
+// TestFold checks that continuation lines are indented and blank lines dropped.
+func TestFold(tt *testing.T) {
+	tests := map[string]string{
+		"value":                      "value",
+		"first\nsecond":              "first\n second",
+		"first\n\n  second  \nthird": "first\n second\n third",
+		"  padded  ":                 "padded",
+	}
+	for value, want := range tests {
+		if got := Fold(value); got != want {
+			tt.Errorf("Fold(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+// TestCheck checks the patterns and the required keys, matched case-insensitively.
+func TestCheck(tt *testing.T) {
+	rules := []Rule{
+		{Key: "Ticket", Pattern: `^[A-Z]+-[0-9]+$`, Required: true},
+		{Key: "Change-Id", Pattern: `^I[0-9a-f]+$`},
+		{Key: "Reviewed-on"},
+	}
+	tests := []struct {
+		name     string
+		trailers []t.Trailer
+		want     []Problem
+	}{
+		{
+			name:     "valid",
+			trailers: []t.Trailer{{Key: "ticket", Value: "PROJ-1"}, {Key: "Change-Id", Value: "I0a"}, {Key: "Other", Value: "x"}},
+			want:     []Problem{},
+		},
+		{
+			name:     "missing required",
+			trailers: []t.Trailer{{Key: "Change-Id", Value: "I0a"}},
+			want:     []Problem{{Key: "Ticket", Index: -1, Message: "missing required trailer Ticket"}},
+		},
+		{
+			name:     "pattern mismatch and empty value",
+			trailers: []t.Trailer{{Key: "Ticket", Value: "proj-1"}, {Key: "Reviewed-on", Value: " "}},
+			want: []Problem{
+				{Key: "Ticket", Index: 0, Message: `Ticket "proj-1" does not match ^[A-Z]+-[0-9]+$`},
+				{Key: "Reviewed-on", Index: 1, Message: "Reviewed-on cannot be empty"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tt.Run(test.name, func(tt *testing.T) {
+			if got := Check(test.trailers, rules); !reflect.DeepEqual(got, test.want) {
+				tt.Errorf("Check() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestValidKey checks the keys git reads as trailer keys.
+func TestValidKey(tt *testing.T) {
+	for key, valid := range map[string]bool{"Signed-off-by": true, "X2": true, "": false, "-Key": false, "Two words": false, "Key:": false} {
+		if err := ValidKey(key); (err == nil) != valid {
+			tt.Errorf("ValidKey(%q) = %v, want valid %v", key, err, valid)
+		}
+	}
+}
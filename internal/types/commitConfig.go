@@ -3,13 +3,18 @@ package types
 
 // CommitConfig holds all information required to format a commit message.
 type CommitConfig struct {
-	Type            CommitType
-	Scope           string
-	Emoji           Emoji
-	Description     string
-	Body            string
-	Breaking        bool
-	BreakingReason  string
-	Reviewers       []string
-	ReferenceIssues []string
+	Type            CommitType       `json:"type,omitempty"`
+	Scope           string           `json:"scope,omitempty"`
+	Emoji           Emoji            `json:"emoji,omitempty"`
+	Description     string           `json:"description,omitempty"`
+	Body            string           `json:"body,omitempty"`
+	Breaking        bool             `json:"breaking,omitempty"`
+	BreakingReason  string           `json:"breakingReason,omitempty"`
+	Reviewers       []string         `json:"reviewers,omitempty"`
+	AckedBy         []string         `json:"ackedBy,omitempty"`
+	TestedBy        []string         `json:"testedBy,omitempty"`
+	ReportedBy      []string         `json:"reportedBy,omitempty"`
+	CoAuthors       []string         `json:"coAuthors,omitempty"`
+	ReferenceIssues []IssueReference `json:"referenceIssues,omitempty"`
+	Trailers        []Trailer        `json:"trailers,omitempty"`
 }
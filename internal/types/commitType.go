@@ -1,8 +1,7 @@
-// Package types defines the structures used for constructing commit configuration data.
 package types
 
 // CommitType represents the commit category with a code and its description.
 type CommitType struct {
-	Code        string
-	Description string
+	Code        string `json:"code,omitempty"`
+	Description string `json:"description,omitempty"`
 }
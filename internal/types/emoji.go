@@ -1,9 +1,8 @@
-// Package types defines the structures used for constructing commit configuration data.
 package types
 
 // Emoji represents an emoji with its symbol, code, and description.
 type Emoji struct {
-	Symbol      string
-	Code        string
-	Description string
+	Symbol      string `json:"symbol,omitempty"`
+	Code        string `json:"code,omitempty"`
+	Description string `json:"description,omitempty"`
 }
@@ -0,0 +1,8 @@
+package types
+
+// IssueReference links the commit to an issue, rendered as a "Relation: Issue" footer
+// such as "Closes: #123".
+type IssueReference struct {
+	Relation string `json:"relation,omitempty"`
+	Issue    string `json:"issue,omitempty"`
+}
@@ -0,0 +1,7 @@
+package types
+
+// Trailer represents a git trailer line such as "Signed-off-by: Jane Doe <jane@example.com>".
+type Trailer struct {
+	Key   string `json:"key,omitempty"`
+	Value string `json:"value,omitempty"`
+}
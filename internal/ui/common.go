@@ -1,35 +1,119 @@
 // Package ui provides user interface helpers using promptui for collecting inputs.
 package ui
 
-import "github.com/manifoldco/promptui"
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"golang.org/x/term"
+)
 
 // ConfirmSelect displays a selection prompt asking for a confirmation (Yes/No).
 // It returns true if "Yes" is selected.
-func ConfirmSelect(label string) (bool, error) {
-	prompt := promptui.Select{
-		Label: label,
-		Items: []string{"No", "Yes"},
+func ConfirmSelect(p Prompter, label string) (bool, error) {
+	return ConfirmSelectDefault(p, label, false)
+}
+
+// ConfirmSelectDefault displays a confirmation prompt like ConfirmSelect, starting
+// on "Yes" when defaultYes is set.
+func ConfirmSelectDefault(p Prompter, label string, defaultYes bool) (bool, error) {
+	cursor := 0
+	if defaultYes {
+		cursor = 1
 	}
 
-	index, _, err := prompt.Run()
+	index, err := p.Select(label, []string{"No", "Yes"}, SelectOptions{Cursor: cursor})
 	if err != nil {
 		return false, err
 	}
 	return index == 1, nil
 }
 
+// IsInterrupt reports whether err comes from the user leaving a prompt with Ctrl+C or
+// Ctrl+D.
+func IsInterrupt(err error) bool {
+	return errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF)
+}
+
 // OptionalInput displays a prompt that allows the user to input an optional value.
 // It returns the entered value or an empty string if omitted.
-func OptionalInput(label string) (string, error) {
-	prompt := promptui.Prompt{
-		Label:     label,
-		Default:   "",
-		AllowEdit: true,
-	}
-
-	result, err := prompt.Run()
+func OptionalInput(p Prompter, label string) (string, error) {
+	result, err := p.Input(label, "", nil)
 	if err != nil {
 		return "", err
 	}
 	return result, nil
 }
+
+// MultilineInput asks for text one line at a time until an empty line or Ctrl+D.
+// It returns the lines joined with newlines, or an empty string if none was entered.
+func MultilineInput(p Prompter, label string) (string, error) {
+	lines := []string{}
+
+	for {
+		line, err := p.Input(fmt.Sprintf("%s [%d]", label, len(lines)+1), "", nil)
+		if errors.Is(err, promptui.ErrEOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if line == "" {
+			break
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// MultiSelect asks to pick any number of items: choosing an item toggles it, and
+// choosing "Done" returns the indices of the picked items in order.
+func MultiSelect(p Prompter, label string, items []string) ([]int, error) {
+	picked := make([]bool, len(items))
+	cursor := 0
+
+	for {
+		rows := []string{"✔ Done"}
+		for i, item := range items {
+			mark := "[ ]"
+			if picked[i] {
+				mark = "[x]"
+			}
+			rows = append(rows, mark+" "+item)
+		}
+
+		index, err := p.Select(label, rows, SelectOptions{
+			Size:   min(len(rows), 12),
+			Cursor: cursor,
+			Searcher: func(input string, index int) bool {
+				return strings.Contains(strings.ToLower(rows[index]), strings.ToLower(input))
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if index == 0 {
+			break
+		}
+		picked[index-1] = !picked[index-1]
+		cursor = index
+	}
+
+	indices := []int{}
+	for i, ok := range picked {
+		if ok {
+			indices = append(indices, i)
+		}
+	}
+	return indices, nil
+}
+
+// IsInteractive reports whether stdin is attached to a terminal,
+// meaning prompts can be displayed and answered.
+func IsInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
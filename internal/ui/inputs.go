@@ -1,31 +1,22 @@
-// Package ui provides user interface helpers using promptui for collecting inputs.
 package ui
 
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
-	d "github.com/GiulianoPoeta99/conventional_commits_cli/internal/data"
 	t "github.com/GiulianoPoeta99/conventional_commits_cli/internal/types"
-
-	"github.com/manifoldco/promptui"
 )
 
 // InputWithValidation displays a prompt with the given label and default value,
 // validating the input using the provided function.
 func InputWithValidation(
+	p Prompter,
 	label string,
 	defaultValue string,
 	validate func(input string) error,
 ) (string, error) {
-	prompt := promptui.Prompt{
-		Label:     label,
-		Default:   defaultValue,
-		AllowEdit: true,
-		Validate:  validate,
-	}
-
-	result, err := prompt.Run()
+	result, err := p.Input(label, defaultValue, validate)
 	if err != nil {
 		return "", err
 	}
@@ -33,26 +24,27 @@ func InputWithValidation(
 }
 
 // SelectCommitType prompts the user to select a commit type from a list of available types.
-// It returns the selected CommitType.
-func SelectCommitType() (t.CommitType, error) {
-	commitTypes := d.GetCommitTypes()
+// When suggestion names one of the types, the cursor starts on it and its detail is
+// shown next to it. It returns the selected CommitType.
+func SelectCommitType(p Prompter, commitTypes []t.CommitType, suggestion Suggestion) (t.CommitType, error) {
 	items := []string{}
+	cursor := 0
 
 	// Format commit types into displayable strings.
-	for _, t := range commitTypes {
-		items = append(
-			items,
-			fmt.Sprintf("%s -> %s", strings.ToUpper(t.Code), t.Description),
-		)
-	}
-
-	prompt := promptui.Select{
-		Label: "Select the type of change that you're committing",
-		Items: items,
-		Size:  len(commitTypes),
+	for i, t := range commitTypes {
+		item := fmt.Sprintf("%s -> %s", strings.ToUpper(t.Code), t.Description)
+		if t.Code == suggestion.Value {
+			item += fmt.Sprintf(" 💡 %s", suggestion.Detail)
+			cursor = i
+		}
+		items = append(items, item)
 	}
 
-	index, _, err := prompt.Run()
+	index, err := p.Select(
+		"Select the type of change that you're committing",
+		items,
+		SelectOptions{Size: len(commitTypes), Cursor: cursor},
+	)
 	if err != nil {
 		return t.CommitType{}, err
 	}
@@ -60,25 +52,14 @@ func SelectCommitType() (t.CommitType, error) {
 }
 
 // SuggestEmojis returns a list of recommended emojis based on the provided commit type.
-func SuggestEmojis(commitType t.CommitType) []t.Emoji {
-	emojis := d.GetEmojis()
+// typeToEmojis maps commit type codes to the codes of their suggested emojis.
+func SuggestEmojis(
+	commitType t.CommitType,
+	emojis []t.Emoji,
+	typeToEmojis map[string][]string,
+) []t.Emoji {
 	suggestions := []t.Emoji{}
 
-	// Map commit types to suggested emoji codes.
-	typeToEmojis := map[string][]string{
-		"feat":     {"sparkles", "rocket", "tada"},
-		"fix":      {"bug", "ambulance", "adhesive_bandage", "goal_net"},
-		"docs":     {"memo", "bulb", "pencil2"},
-		"style":    {"art", "lipstick"},
-		"refactor": {"recycle", "hammer", "truck"},
-		"perf":     {"zap", "chart_with_upwards_trend"},
-		"test":     {"white_check_mark", "test_tube"},
-		"build":    {"package", "construction_worker"},
-		"ci":       {"green_heart", "construction"},
-		"chore":    {"wrench", "bricks"},
-		"revert":   {"rewind", "coffin"},
-	}
-
 	// Filter emojis based on suggested codes.
 	if emojiCodes, ok := typeToEmojis[commitType.Code]; ok {
 		for _, code := range emojiCodes {
@@ -94,10 +75,16 @@ func SuggestEmojis(commitType t.CommitType) []t.Emoji {
 }
 
 // SelectEmojiWithSuggestions allows the user to select an emoji.
-// It provides recommendations based on the commit type, placing them at the top.
-func SelectEmojiWithSuggestions(commitType t.CommitType) (t.Emoji, error) {
-	suggestions := SuggestEmojis(commitType)
-	allEmojis := d.GetEmojis()
+// It provides recommendations based on the commit type, placing them at the top,
+// and starts on the current emoji when there is one.
+func SelectEmojiWithSuggestions(
+	p Prompter,
+	commitType t.CommitType,
+	current t.Emoji,
+	allEmojis []t.Emoji,
+	typeToEmojis map[string][]string,
+) (t.Emoji, error) {
+	suggestions := SuggestEmojis(commitType, allEmojis, typeToEmojis)
 
 	// Merge the suggestions and the rest of the emojis.
 	displayEmojis := append([]t.Emoji{}, suggestions...)
@@ -117,9 +104,13 @@ func SelectEmojiWithSuggestions(commitType t.CommitType) (t.Emoji, error) {
 	}
 
 	items := []string{}
+	cursor := 0
 
 	// Format the list of emojis for display; add a prefix for recommended ones.
 	for i, e := range displayEmojis {
+		if e.Code == current.Code {
+			cursor = i
+		}
 		prefix := ""
 		if i < len(suggestions) {
 			prefix = "🔍 "
@@ -134,22 +125,81 @@ func SelectEmojiWithSuggestions(commitType t.CommitType) (t.Emoji, error) {
 	}
 
 	// Create a prompt with search capability.
-	prompt := promptui.Select{
-		Label:        "Select an emoji (🔍 = Recommendation)",
-		Items:        items,
-		Size:         10,
-		CursorPos:    0,
-		HideSelected: false,
+	index, err := p.Select("Select an emoji (🔍 = Recommendation)", items, SelectOptions{
+		Size:   10,
+		Cursor: cursor,
 		Searcher: func(input string, index int) bool {
 			item := strings.ToLower(items[index])
 			input = strings.ToLower(input)
 			return strings.Contains(item, input)
 		},
-	}
-
-	index, _, err := prompt.Run()
+	})
 	if err != nil {
 		return t.Emoji{}, err
 	}
 	return displayEmojis[index], nil
 }
+
+// Suggestion is a value proposed by a selection prompt, with the reason it is proposed.
+type Suggestion struct {
+	Value  string
+	Detail string
+}
+
+// SelectSuggestion asks to pick one of the suggestions, to type a custom value or to
+// leave the value empty. The list is searchable with fuzzy matching; customLabel is
+// the label of the custom value prompt, whose non-empty values must pass validate.
+func SelectSuggestion(p Prompter, label, customLabel string, suggestions []Suggestion, validate func(input string) error) (string, error) {
+	items := []string{}
+
+	// Format the suggestions with their details, followed by the custom and empty choices.
+	for _, s := range suggestions {
+		item := s.Value
+		if s.Detail != "" {
+			item += " (" + s.Detail + ")"
+		}
+		items = append(items, item)
+	}
+	custom := len(items)
+	items = append(items, "custom…", "none")
+
+	index, err := p.Select(label, items, SelectOptions{
+		Size: min(len(items), 10),
+		Searcher: func(input string, index int) bool {
+			return FuzzyMatch(input, items[index])
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	switch index {
+	case custom:
+		return p.Input(customLabel, "", func(input string) error {
+			if input == "" || validate == nil {
+				return nil
+			}
+			return validate(input)
+		})
+	case custom + 1:
+		return "", nil
+	}
+	return suggestions[index].Value, nil
+}
+
+// FuzzyMatch reports whether the characters of input appear in item in the same
+// order, ignoring case and spaces, so "apgw" matches "api-gateway".
+func FuzzyMatch(input, item string) bool {
+	item = strings.ToLower(item)
+	for _, r := range strings.ToLower(input) {
+		if r == ' ' {
+			continue
+		}
+		i := strings.IndexRune(item, r)
+		if i < 0 {
+			return false
+		}
+		item = item[i+utf8.RuneLen(r):]
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"io"
+
+	"github.com/manifoldco/promptui"
+)
+
+// SelectOptions tunes a selection prompt.
+type SelectOptions struct {
+	// Size is the number of visible items; zero uses the frontend default.
+	Size int
+	// Cursor is the index of the initially highlighted item.
+	Cursor int
+	// Searcher enables searching when set; it reports whether the item at index matches input.
+	Searcher func(input string, index int) bool
+}
+
+// Prompter is the frontend asking the questions of the wizard.
+type Prompter interface {
+	// Select asks to pick one of items and returns its index.
+	Select(label string, items []string, opts SelectOptions) (int, error)
+	// Input asks for a line of text, pre-filled with defaultValue.
+	// validate, when not nil, must accept the value before it is returned.
+	Input(label, defaultValue string, validate func(input string) error) (string, error)
+}
+
+// Promptui is the terminal Prompter built on promptui.
+type Promptui struct {
+	// Stdin and Stdout are the streams of the prompts; nil means the process streams.
+	// They let the wizard run from a git hook against /dev/tty.
+	Stdin  io.ReadCloser
+	Stdout io.WriteCloser
+}
+
+// NewPromptui returns a Promptui using the process streams.
+func NewPromptui() *Promptui {
+	return &Promptui{}
+}
+
+// Select displays a promptui selection list.
+func (p *Promptui) Select(label string, items []string, opts SelectOptions) (int, error) {
+	prompt := promptui.Select{
+		Label:     label,
+		Items:     items,
+		Size:      opts.Size,
+		CursorPos: opts.Cursor,
+		Stdin:     p.Stdin,
+		Stdout:    p.Stdout,
+	}
+	if opts.Searcher != nil {
+		prompt.Searcher = opts.Searcher
+	}
+
+	// promptui rejects a cursor position outside the visible window.
+	if prompt.Size > 0 && opts.Cursor >= prompt.Size {
+		index, _, err := prompt.RunCursorAt(opts.Cursor, opts.Cursor-prompt.Size+1)
+		return index, err
+	}
+
+	index, _, err := prompt.Run()
+	return index, err
+}
+
+// Input displays a promptui text prompt.
+func (p *Promptui) Input(label, defaultValue string, validate func(input string) error) (string, error) {
+	prompt := promptui.Prompt{
+		Label:     label,
+		Default:   defaultValue,
+		AllowEdit: true,
+		Stdin:     p.Stdin,
+		Stdout:    p.Stdout,
+	}
+	if validate != nil {
+		prompt.Validate = validate
+	}
+
+	return prompt.Run()
+}
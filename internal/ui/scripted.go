@@ -0,0 +1,198 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrScriptExhausted is returned when a scripted prompt has no answer left.
+var ErrScriptExhausted = errors.New("answers script exhausted")
+
+// Answer is one scripted reply.
+type Answer struct {
+	// Prompt, when set, must appear in the label of the question being answered
+	// (case-insensitively), which keeps scripts aligned with the wizard.
+	Prompt string `json:"prompt,omitempty" yaml:"prompt,omitempty"`
+	// Value is the text typed at an input, or the item chosen at a selection:
+	// the first item equal to it, then starting with it, then containing it.
+	// An empty value accepts the item under the initial cursor, or the default text
+	// of an input.
+	Value string `json:"value" yaml:"value"`
+	// Interrupt answers the question with Ctrl+C instead of a value.
+	Interrupt bool `json:"interrupt,omitempty" yaml:"interrupt,omitempty"`
+}
+
+// UnmarshalYAML accepts a plain scalar as a shorthand for an answer with only a value.
+func (a *Answer) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		a.Value = node.Value
+		return nil
+	}
+
+	type plain Answer
+	return node.Decode((*plain)(a))
+}
+
+// UnmarshalJSON accepts a plain string as a shorthand for an answer with only a value.
+func (a *Answer) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &a.Value)
+	}
+
+	type plain Answer
+	return json.Unmarshal(data, (*plain)(a))
+}
+
+// script is the layout of an answers file.
+type script struct {
+	Answers []Answer `json:"answers" yaml:"answers"`
+}
+
+// Scripted is a Prompter replaying a queue of answers, for tests and automation.
+// An answer rejected by the validation of an input is reported and the next one
+// is tried, exactly like a user retyping it.
+type Scripted struct {
+	// Answers are consumed in order, one per question or validation retry.
+	Answers []Answer
+	// Transcript, when set, receives every question with the answer given to it,
+	// which makes the runs suitable for golden files.
+	Transcript io.Writer
+
+	next int
+}
+
+// NewScripted returns a Scripted replaying answers.
+func NewScripted(answers ...Answer) *Scripted {
+	return &Scripted{Answers: answers}
+}
+
+// LoadScript reads a Scripted from a YAML or JSON file holding an `answers` list.
+// Each answer is either a plain value or an object with value, prompt and interrupt.
+func LoadScript(path string) (*Scripted, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var s script
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&s)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&s)
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return NewScripted(s.Answers...), nil
+}
+
+// Remaining returns the number of answers not consumed yet.
+func (s *Scripted) Remaining() int {
+	return len(s.Answers) - s.next
+}
+
+// answer pops the next answer for the question labelled label.
+func (s *Scripted) answer(label string) (Answer, error) {
+	s.printf("? %s\n", label)
+
+	if s.next >= len(s.Answers) {
+		return Answer{}, fmt.Errorf("%w at %q", ErrScriptExhausted, label)
+	}
+	a := s.Answers[s.next]
+	s.next++
+
+	if a.Prompt != "" && !strings.Contains(strings.ToLower(label), strings.ToLower(a.Prompt)) {
+		return Answer{}, fmt.Errorf("answer %d expects prompt %q, got %q", s.next, a.Prompt, label)
+	}
+	if a.Interrupt {
+		s.printf("^C\n")
+		return Answer{}, promptui.ErrInterrupt
+	}
+	return a, nil
+}
+
+// printf writes to the transcript, if any.
+func (s *Scripted) printf(format string, args ...any) {
+	if s.Transcript != nil {
+		fmt.Fprintf(s.Transcript, format, args...)
+	}
+}
+
+// Select picks the item matching the next answer.
+func (s *Scripted) Select(label string, items []string, opts SelectOptions) (int, error) {
+	a, err := s.answer(label)
+	if err != nil {
+		return 0, err
+	}
+
+	index := opts.Cursor
+	if a.Value != "" {
+		index = matchItem(items, a.Value)
+	}
+	if index < 0 || index >= len(items) {
+		return 0, fmt.Errorf("answer %d: no item of %q matches %q", s.next, label, a.Value)
+	}
+
+	s.printf("> %s\n", items[index])
+	return index, nil
+}
+
+// matchItem returns the index of the first item equal to value, then starting with it,
+// then containing it, ignoring case; it returns -1 when none matches.
+func matchItem(items []string, value string) int {
+	value = strings.ToLower(strings.TrimSpace(value))
+	matchers := []func(item string) bool{
+		func(item string) bool { return item == value },
+		func(item string) bool { return strings.HasPrefix(item, value) },
+		func(item string) bool { return strings.Contains(item, value) },
+	}
+
+	for _, matches := range matchers {
+		for i, item := range items {
+			if matches(strings.ToLower(item)) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Input returns the next answer accepted by validate, consuming one answer per attempt.
+// Answers hold the whole text of the input; an empty answer keeps the default value.
+func (s *Scripted) Input(label, defaultValue string, validate func(input string) error) (string, error) {
+	for {
+		a, err := s.answer(label)
+		if err != nil {
+			return "", err
+		}
+		if a.Value == "" {
+			a.Value = defaultValue
+		}
+		s.printf("> %s\n", a.Value)
+
+		if validate != nil {
+			if err := validate(a.Value); err != nil {
+				s.printf("! %v\n", err)
+				continue
+			}
+		}
+		return a.Value, nil
+	}
+}
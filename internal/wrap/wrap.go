@@ -0,0 +1,135 @@
+// Package wrap reflows commit message bodies and footers to a maximum line width.
+package wrap
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultWidth is the column at which bodies and footers are wrapped by default,
+// the width git log and most tooling are designed for.
+const DefaultWidth = 72
+
+// bulletPattern matches the marker of a list item, e.g. "- ", "* " or "12. ".
+var bulletPattern = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+`)
+
+// Body wraps the paragraphs and list items of text at width columns.
+// Blocks whose lines all fit are left exactly as written; the others are reflowed.
+// Lines indented by four spaces or a tab are code and are never changed, and words
+// such as URLs are never split, even when longer than width.
+// A zero width returns text unchanged.
+func Body(text string, width int) string {
+	if width <= 0 || text == "" {
+		return text
+	}
+
+	out := []string{}
+	block := []string{}
+	indent := ""
+
+	// flush writes the pending paragraph or list item.
+	flush := func() {
+		if len(block) == 0 {
+			return
+		}
+		out = append(out, reflow(block, indent, width)...)
+		block = nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+			out = append(out, line)
+		case isCode(line) && len(block) == 0:
+			out = append(out, line)
+		case bulletPattern.MatchString(line):
+			// Each item is reflowed on its own, aligning its lines after the marker.
+			flush()
+			block = []string{line}
+			indent = strings.Repeat(" ", utf8.RuneCountInString(bulletPattern.FindString(line)))
+		default:
+			if len(block) == 0 {
+				indent = ""
+			}
+			block = append(block, line)
+		}
+	}
+	flush()
+
+	return strings.Join(out, "\n")
+}
+
+// Footer wraps the value of a footer so that "key: value" fits in width columns.
+// Continuation lines start with a space, which git and the parser read as part of
+// the same footer. A zero width returns value unchanged.
+func Footer(key, value string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(key)+2+utf8.RuneCountInString(value) <= width {
+		return value
+	}
+
+	words := strings.Fields(value)
+	if len(words) == 0 {
+		return value
+	}
+
+	// Wrap as if the key was glued to the first word, then strip it again.
+	words[0] = key + ": " + words[0]
+	lines := fill(words, " ", width)
+	lines[0] = strings.TrimPrefix(lines[0], key+": ")
+	return strings.Join(lines, "\n")
+}
+
+// isCode reports whether line belongs to an indented code block.
+func isCode(line string) bool {
+	return strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")
+}
+
+// reflow rewraps the lines of a paragraph or list item when one of them is too long.
+// Continuation lines are indented with indent.
+func reflow(lines []string, indent string, width int) []string {
+	tooLong := false
+	for _, line := range lines {
+		if utf8.RuneCountInString(line) > width {
+			tooLong = true
+			break
+		}
+	}
+	if !tooLong {
+		return lines
+	}
+
+	// The leading whitespace and marker of the first line are kept verbatim.
+	prefix := ""
+	if match := bulletPattern.FindString(lines[0]); match != "" {
+		prefix = match
+	}
+	words := strings.Fields(strings.TrimPrefix(strings.Join(lines, " "), prefix))
+	if len(words) == 0 {
+		return lines
+	}
+	words[0] = prefix + words[0]
+
+	return fill(words, indent, width)
+}
+
+// fill lays words out greedily on lines of at most width columns, starting the
+// lines after the first with indent. A word longer than width gets a line of its own.
+func fill(words []string, indent string, width int) []string {
+	lines := []string{}
+	line := ""
+
+	for _, word := range words {
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = indent + word
+		}
+	}
+	return append(lines, line)
+}
@@ -0,0 +1,123 @@
+package wrap
+
+import "testing"
+
+func TestBody(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "lines that fit are kept",
+			text: "one\ntwo three\n\nfour",
+			want: "one\ntwo three\n\nfour",
+		},
+		{
+			name: "paragraph",
+			text: "the quick brown fox jumps over the lazy dog",
+			want: "the quick brown fox\njumps over the lazy\ndog",
+		},
+		{
+			name: "paragraphs reflowed separately",
+			text: "short\nline\n\nthe quick brown fox jumps\nover",
+			want: "short\nline\n\nthe quick brown fox\njumps over",
+		},
+		{
+			name: "bullet list",
+			text: "- first item that is rather long\n- second\n* third item, long enough too",
+			want: "- first item that is\n  rather long\n- second\n* third item, long\n  enough too",
+		},
+		{
+			name: "numbered list",
+			text: "10. alpha beta gamma delta\n2) one",
+			want: "10. alpha beta gamma\n    delta\n2) one",
+		},
+		{
+			name: "nested bullet",
+			text: "  - nested item is long enough",
+			want: "  - nested item is\n    long enough",
+		},
+		{
+			name: "continuation line of an item",
+			text: "- item with a continuation\n  line that goes on",
+			want: "- item with a\n  continuation line\n  that goes on",
+		},
+		{
+			name: "code block",
+			text: "Run it:\n\n    go test ./... -run TestBody -count=1\n\tmake lint all the things",
+			want: "Run it:\n\n    go test ./... -run TestBody -count=1\n\tmake lint all the things",
+		},
+		{
+			name: "url",
+			text: "see https://example.com/a/very/long/path here",
+			want: "see\nhttps://example.com/a/very/long/path\nhere",
+		},
+		{
+			name: "blank lines kept verbatim",
+			text: "the quick brown fox jumps\n  \nover",
+			want: "the quick brown fox\njumps\n  \nover",
+		},
+		{
+			name: "multibyte characters",
+			text: "ñandú ñandú ñandú ñandú",
+			want: "ñandú ñandú ñandú\nñandú",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Body(test.text, 20); got != test.want {
+				t.Errorf("Body(%q, 20) =\n%s\nwant\n%s", test.text, got, test.want)
+			}
+		})
+	}
+
+	text := "the quick brown fox jumps over the lazy dog"
+	if got := Body(text, 0); got != text {
+		t.Errorf("Body(%q, 0) = %q, want it unchanged", text, got)
+	}
+}
+
+func TestFooter(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value string
+		width int
+		want  string
+	}{
+		{name: "fits", key: "Refs", value: "#1, #2", width: 30, want: "#1, #2"},
+		{name: "zero width", key: "Refs", value: "#101, #102, #103, #104, #105, #106", width: 0, want: "#101, #102, #103, #104, #105, #106"},
+		{name: "wrapped", key: "Refs", value: "#101, #102, #103, #104, #105, #106", width: 30, want: "#101, #102, #103, #104,\n #105, #106"},
+		{
+			name:  "breaking change",
+			key:   "BREAKING CHANGE",
+			value: "the config file moved to the user directory",
+			width: 30,
+			want:  "the config\n file moved to the user\n directory",
+		},
+		{
+			name:  "url",
+			key:   "See-also",
+			value: "https://example.com/a/very/long/url/beyond/the/width",
+			width: 30,
+			want:  "https://example.com/a/very/long/url/beyond/the/width",
+		},
+		{
+			name:  "url after words",
+			key:   "See-also",
+			value: "the design at https://example.com/a/very/long/url",
+			width: 30,
+			want:  "the design at\n https://example.com/a/very/long/url",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Footer(test.key, test.value, test.width); got != test.want {
+				t.Errorf("Footer(%q, %q, %d) =\n%s\nwant\n%s", test.key, test.value, test.width, got, test.want)
+			}
+		})
+	}
+}